@@ -3,12 +3,15 @@ package alfalfa
 
 import (
 	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
-	"github.com/drpsychick/go-alexa-lambda"
-	"github.com/drpsychick/go-alexa-lambda/l10n"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
 	"github.com/hamba/pkg/log"
 	"github.com/hamba/pkg/stats"
 )
 
+// imageURL is the base URL of the images of responses.
+const imageURL = "https://raw.githubusercontent.com/DrPsychick/alexa-go-cloudformation-demo/master/alexa/assets/images/"
+
 // Config defines additional data that can be provided and used in requests.
 type Config struct {
 	User string
@@ -135,8 +138,11 @@ func (a *Application) AWSStatus(loc l10n.LocaleInstance, area, region string) (a
 		Title:  title,
 		Text:   msg,
 		Speech: msgSSML,
-		Image:  "https://raw.githubusercontent.com/DrPsychick/alexa-go-cloudformation-demo/master/alexa/assets/images/de-DE_%s.png", //nolint:lll
-		End:    true,
+		Image: &alexa.Image{
+			SmallImageURL: imageURL + "de-DE_small.png",
+			LargeImageURL: imageURL + "de-DE_large.png",
+		},
+		End: true,
 	}, nil
 }
 
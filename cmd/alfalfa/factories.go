@@ -1,9 +1,19 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	alfalfa "github.com/drpsychick/alexa-go-cloudformation-demo"
-	"github.com/drpsychick/go-alexa-lambda/skill"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/lambda"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/skill"
 	"github.com/hamba/cmd"
+	"github.com/hamba/pkg/log"
+	"github.com/hamba/pkg/stats"
+	"github.com/urfave/cli/v2"
 )
 
 func newApplication(c *cmd.Context) (*alfalfa.Application, error) { //nolint:unparam
@@ -15,10 +25,45 @@ func newApplication(c *cmd.Context) (*alfalfa.Application, error) { //nolint:unp
 	return app, nil
 }
 
+// newStats attaches the statter of the stats flags to the context, using the logger of the context.
+//
+// It logs if the stats DSN flag is empty.
+func newStats(c *cli.Context, ctx *cmd.Context) error {
+	st, err := cmd.NewStats(c, ctx.Logger())
+	if err != nil {
+		return err
+	}
+	if st == stats.Null {
+		log.Info(ctx, fmt.Sprintf("Flag '%s' is empty!", cmd.FlagStatsDSN))
+	}
+	ctx.AttachStatter(func(s stats.Statter) stats.Statter {
+		_ = s.Close()
+		return st
+	})
+	return nil
+}
+
 func newSkill() *skill.SkillBuilder {
 	return alfalfa.NewSkill()
 }
 
-func createSkillModels(s *skill.SkillBuilder) (map[string]*skill.Model, error) {
-	return alfalfa.CreateSkillModels(s)
+func createSkillModels(app *alfalfa.Application, s *skill.SkillBuilder) (map[string]*skill.Model, error) {
+	return alfalfa.CreateSkillModels(s, lambda.Intents(app))
+}
+
+func newPersistence(c *cli.Context) (alexa.PersistenceAdapter, error) {
+	if path := c.String(FlagPersistenceFile); path != "" {
+		return alexa.NewFilePersistence(path)
+	}
+
+	return alexa.NewMemoryPersistence(), nil
+}
+
+// newLocaleRegistry returns a registry of the locale files in the directory, reloaded when they change.
+func newLocaleRegistry(c *cmd.Context, dir string) (*l10n.ReloadableRegistry, error) {
+	return l10n.NewReloadableRegistry(os.DirFS(dir), ".",
+		l10n.WithDefaultLocale(loca.Registry.GetDefault().GetName()),
+		l10n.WithStats(c),
+		l10n.WithLogger(c),
+	)
 }
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/urfave/cli/v2"
+)
+
+// L10n flag constants.
+const (
+	FlagLocale = "locale"
+	FlagFormat = "format"
+	FlagOutput = "output"
+	FlagInput  = "input"
+	FlagDir    = "dir"
+)
+
+// Translation file formats.
+const (
+	FormatXLIFF = "xliff"
+	FormatPO    = "po"
+)
+
+// localeExtensions are the locale file extensions, in order of preference.
+var localeExtensions = []string{".yaml", ".yml", ".json", ".toml"}
+
+func runL10nExport(c *cli.Context) error {
+	source, ok := loca.Registry.GetDefault().(*l10n.Locale)
+	if !ok {
+		return fmt.Errorf("default locale not found")
+	}
+
+	name := c.String(FlagLocale)
+	target, ok := loca.Registry.GetLocales()[name].(*l10n.Locale)
+	if !ok {
+		// start a new translation
+		target = l10n.NewLocale(name)
+	}
+	t := l10n.NewTranslation(source, target)
+
+	var w io.Writer = c.App.Writer
+	if file := c.String(FlagOutput); file != "" {
+		f, err := os.Create(file) //nolint:gosec
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck
+		w = f
+	}
+
+	switch translationFormat(c, c.String(FlagOutput)) {
+	case FormatXLIFF:
+		return l10n.WriteXLIFF(w, t)
+	case FormatPO:
+		return l10n.WritePO(w, t)
+	default:
+		return fmt.Errorf("unsupported format '%s'", c.String(FlagFormat))
+	}
+}
+
+func runL10nImport(c *cli.Context) error {
+	file := c.String(FlagInput)
+	f, err := os.Open(file) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	var t *l10n.Translation
+	switch translationFormat(c, file) {
+	case FormatXLIFF:
+		t, err = l10n.ReadXLIFF(f)
+	case FormatPO:
+		t, err = l10n.ReadPO(f)
+	default:
+		return fmt.Errorf("unsupported format '%s'", c.String(FlagFormat))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if t.TargetLocale == "" {
+		return fmt.Errorf("%s: no target locale", file)
+	}
+
+	out := localeFile(c.String(FlagDir), t.TargetLocale)
+	l := l10n.NewLocale(t.TargetLocale)
+	if _, err = os.Stat(out); err == nil {
+		if l, err = l10n.LoadFile(out); err != nil {
+			return err
+		}
+	}
+	if err = l10n.WriteFile(out, t.Merge(l)); err != nil {
+		return err
+	}
+
+	var missing int
+	for _, u := range t.Units {
+		if u.Missing {
+			missing++
+		}
+	}
+	_, err = fmt.Fprintf(c.App.Writer, "wrote %s (%d units, %d missing)\n", out, len(t.Units), missing)
+	return err
+}
+
+func runL10nLint(c *cli.Context) error {
+	issues := l10n.Lint(loca.Registry)
+	for _, i := range issues {
+		if _, err := fmt.Fprintln(c.App.Writer, i.Error()); err != nil {
+			return err
+		}
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%d l10n issues found", len(issues))
+	}
+	return nil
+}
+
+// translationFormat returns the format flag or the format matching the file extension.
+func translationFormat(c *cli.Context, file string) string {
+	if c.IsSet(FlagFormat) {
+		return strings.ToLower(c.String(FlagFormat))
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".po", ".pot":
+		return FormatPO
+	case ".xlf", ".xliff":
+		return FormatXLIFF
+	}
+	return c.String(FlagFormat)
+}
+
+// localeFile returns the existing file of the locale in dir or a new YAML file.
+func localeFile(dir, locale string) string {
+	for _, ext := range localeExtensions {
+		file := filepath.Join(dir, locale+ext)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	return filepath.Join(dir, locale+localeExtensions[0])
+}
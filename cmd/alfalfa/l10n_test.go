@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+)
+
+func TestL10nExportImport(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, file := range []string{"de-DE.xlf", "de-DE.po"} {
+		app := cli.NewApp()
+		app.Commands = commands
+		var out bytes.Buffer
+		app.Writer = &out
+
+		err := app.Run([]string{"alfalfa", "l10n", "export", "--locale", "de-DE", "--output", filepath.Join(dir, file)})
+		assert.NoError(t, err)
+
+		err = app.Run([]string{"alfalfa", "l10n", "import", "--input", filepath.Join(dir, file), "--dir", dir})
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), "de-DE.yaml")
+
+		l, err := l10n.LoadFile(filepath.Join(dir, "de-DE.yaml"))
+		assert.NoError(t, err)
+		de := loca.Registry.GetLocales()["de-DE"].(*l10n.Locale)
+		assert.Equal(t, de.TextSnippets, l.TextSnippets)
+	}
+}
+
+func TestL10nImport_MergesLocale(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "de-DE.yaml")
+	existing := l10n.NewLocale("de-DE")
+	existing.Set("Only_In_File", []string{"bleibt"})
+	assert.NoError(t, l10n.WriteFile(file, existing))
+
+	app := cli.NewApp()
+	app.Commands = commands
+	app.Writer = &bytes.Buffer{}
+
+	err := app.Run([]string{"alfalfa", "l10n", "export", "--locale", "de-DE", "--output", filepath.Join(dir, "de-DE.po")})
+	assert.NoError(t, err)
+	err = app.Run([]string{"alfalfa", "l10n", "import", "--input", filepath.Join(dir, "de-DE.po"), "--dir", dir})
+	assert.NoError(t, err)
+
+	l, err := l10n.LoadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bleibt"}, l.TextSnippets["Only_In_File"])
+	de := loca.Registry.GetLocales()["de-DE"].(*l10n.Locale)
+	for k, v := range de.TextSnippets {
+		if assert.NotEmpty(t, l.TextSnippets[k], k) {
+			assert.Equal(t, v[0], l.TextSnippets[k][0], k)
+		}
+	}
+}
+
+func TestL10nLint(t *testing.T) {
+	app := cli.NewApp()
+	app.Commands = commands
+	var out bytes.Buffer
+	app.Writer = &out
+
+	err := app.Run([]string{"alfalfa", "l10n", "lint"})
+	assert.NoError(t, err)
+	assert.Empty(t, out.String())
+}
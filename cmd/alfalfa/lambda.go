@@ -9,8 +9,8 @@ import (
 	alfalfa "github.com/drpsychick/alexa-go-cloudformation-demo"
 	"github.com/drpsychick/alexa-go-cloudformation-demo/lambda"
 	"github.com/drpsychick/alexa-go-cloudformation-demo/lambda/middleware"
-	"github.com/drpsychick/go-alexa-lambda"
-	"github.com/drpsychick/go-alexa-lambda/skill"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/ssml"
 	"github.com/hamba/cmd"
 	"github.com/hamba/logger"
 	"github.com/hamba/pkg/log"
@@ -32,26 +32,26 @@ func runLambda(c *cli.Context) error {
 		return lg
 	})
 
-	st, err := cmd.NewStats(c, lg)
-	if err != nil {
+	if err := newStats(c, ctx); err != nil {
 		return err
 	}
-	if st == stats.Null {
-		log.Info(ctx, fmt.Sprintf("Flag '%s' is empty!", cmd.FlagStatsDSN))
-	}
-	ctx.AttachStatter(func(s stats.Statter) stats.Statter {
-		return st
-	})
 
 	app, err := newApplication(ctx)
 	if err != nil {
 		log.Fatal(ctx, err.Error())
 	}
 	stats.Timing(ctx, "Boot", time.Since(start), 1.0)
-	sb := newSkill()
-	l := newLambda(app, sb)
+	pa, err := newPersistence(c)
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
+	if c.String(FlagPersistenceFile) == "" {
+		log.Info(ctx, fmt.Sprintf("Flag '%s' is empty! Persistent attributes are lost on every cold start.",
+			FlagPersistenceFile))
+	}
+	l := newLambda(app, pa, c.StringSlice(FlagApplicationID)...)
 
-	ms, err := sb.BuildModels()
+	ms, err := createSkillModels(app, newSkill())
 	if err != nil {
 		log.Fatal(ctx, err)
 	}
@@ -70,9 +70,18 @@ func runLambda(c *cli.Context) error {
 	return errors.New("Serve() should not have returned")
 }
 
-func newLambda(app *alfalfa.Application, sb *skill.SkillBuilder) alexa.Handler {
-	h := lambda.NewMux(app, sb)
+// Skill flag constants.
+const (
+	FlagApplicationID   = "alexa.application-id"
+	FlagPersistenceFile = "persistence.file"
+)
+
+func newLambda(app *alfalfa.Application, pa alexa.PersistenceAdapter, ids ...string) alexa.Handler {
+	h := lambda.NewMux(app, lambda.WithProgressiveResponder(alexa.NewProgressiveClient()))
 
+	h = middleware.WithSpeechBudget(h, app, ssml.NewBudget(), true)
+	h = middleware.WithPersistence(h, app, pa)
 	h = middleware.WithRequestStats(h, app)
+	h = middleware.WithApplicationID(h, app, ids...)
 	return middleware.WithRecovery(h, app)
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/hamba/cmd"
 	_ "github.com/joho/godotenv/autoload"
@@ -11,12 +12,53 @@ import (
 
 var version = "v0.0.1"
 
+var skillFlags = cmd.Flags{
+	&cli.StringSliceFlag{
+		Name:    FlagApplicationID,
+		Usage:   "Application IDs (skill IDs) allowed to invoke the skill, all if empty",
+		EnvVars: []string{"ALFALFA_APPLICATION_IDS"},
+	},
+	&cli.StringFlag{
+		Name:    FlagPersistenceFile,
+		Usage:   "File to persist user attributes in, kept in memory if empty",
+		EnvVars: []string{"ALFALFA_PERSISTENCE_FILE"},
+	},
+}
+
 var commands = []*cli.Command{
 	{
 		Name:   "server",
-		Usage:  "Run the lambda server",
+		Usage:  "Run the skill as HTTP(S) server",
 		Action: runServer,
-		Flags:  cmd.Flags{}.Merge(cmd.CommonFlags, cmd.ServerFlags),
+		Flags: cmd.Flags{
+			&cli.StringFlag{
+				Name:    FlagTLSCert,
+				Usage:   "TLS certificate file, serves HTTPS when set",
+				EnvVars: []string{"ALFALFA_TLS_CERT"},
+			},
+			&cli.StringFlag{
+				Name:    FlagTLSKey,
+				Usage:   "TLS private key file",
+				EnvVars: []string{"ALFALFA_TLS_KEY"},
+			},
+			&cli.BoolFlag{
+				Name:    FlagVerify,
+				Value:   true,
+				Usage:   "Verify the Alexa signature and timestamp of requests",
+				EnvVars: []string{"ALFALFA_VERIFY"},
+			},
+			&cli.StringFlag{
+				Name:    FlagLocalesDir,
+				Usage:   "Directory of locale files to use instead of the embedded ones, reloaded when they change",
+				EnvVars: []string{"ALFALFA_LOCALES_DIR"},
+			},
+			&cli.DurationFlag{
+				Name:    FlagLocalesInterval,
+				Value:   10 * time.Second,
+				Usage:   "Interval to check the locale files for changes",
+				EnvVars: []string{"ALFALFA_LOCALES_INTERVAL"},
+			},
+		}.Merge(skillFlags, cmd.CommonFlags, cmd.ServerFlags),
 	},
 	{
 		Name:   "lambda",
@@ -28,7 +70,7 @@ var commands = []*cli.Command{
 				Usage:   "Port on which lambda will listen",
 				EnvVars: []string{"_LAMBDA_SERVER_PORT"},
 			},
-		}.Merge(cmd.CommonFlags, cmd.ServerFlags),
+		}.Merge(skillFlags, cmd.CommonFlags, cmd.ServerFlags),
 	},
 	{
 		Name:  "make",
@@ -47,6 +89,60 @@ var commands = []*cli.Command{
 		}.Merge(cmd.CommonFlags, cmd.ServerFlags),
 		Action: runMake,
 	},
+	{
+		Name:  "l10n",
+		Usage: "Exchange and check translations",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "Export the translations of a locale relative to the default locale as XLIFF or PO",
+				Action: runL10nExport,
+				Flags: cmd.Flags{
+					&cli.StringFlag{
+						Name:     FlagLocale,
+						Usage:    "Target locale to export, e.g. de-DE",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  FlagFormat,
+						Value: FormatXLIFF,
+						Usage: "Format of the file (xliff, po), defaults to the extension of the output file",
+					},
+					&cli.StringFlag{
+						Name:  FlagOutput,
+						Usage: "File to write to, stdout if empty",
+					},
+				},
+			},
+			{
+				Name:   "import",
+				Usage:  "Import translations from XLIFF or PO into the locale file of the target locale",
+				Action: runL10nImport,
+				Flags: cmd.Flags{
+					&cli.StringFlag{
+						Name:     FlagInput,
+						Usage:    "File to import",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  FlagFormat,
+						Value: FormatXLIFF,
+						Usage: "Format of the file (xliff, po), defaults to the extension of the input file",
+					},
+					&cli.StringFlag{
+						Name:  FlagDir,
+						Value: "loca/locales",
+						Usage: "Directory of the locale files",
+					},
+				},
+			},
+			{
+				Name:   "lint",
+				Usage:  "Check all locales for missing keys, placeholder mismatches and invalid SSML",
+				Action: runL10nLint,
+			},
+		},
+	},
 }
 
 func main() {
@@ -56,7 +152,7 @@ func main() {
 	app.Version = version
 	app.Commands = commands
 	// need to be set for default Action
-	app.Flags = cmd.CommonFlags.Merge(cmd.ServerFlags)
+	app.Flags = skillFlags.Merge(cmd.CommonFlags, cmd.ServerFlags)
 	app.Action = runLambda
 
 	if err := app.Run(os.Args); err != nil {
@@ -28,13 +28,9 @@ func runMake(c *cli.Context) error {
 		log.Fatal(ctx, err.Error())
 	}
 
-	// build skill and models
+	// build skill and models from the intents defined in lambda
 	sk := newSkill()
-
-	// lambda injects supported intents, slots, types
-	newLambda(app, sk)
-
-	ms, err := createSkillModels(sk)
+	ms, err := createSkillModels(app, sk)
 	if err != nil {
 		return err
 	}
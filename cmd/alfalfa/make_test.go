@@ -2,12 +2,18 @@ package main
 
 import (
 	"encoding/json"
+	"os"
+	"testing"
+
 	"github.com/drpsychick/alexa-go-cloudformation-demo"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/skill"
 	"github.com/hamba/logger"
+	"github.com/hamba/pkg/log"
+	"github.com/hamba/pkg/stats"
 	"github.com/hamba/statter/l2met"
 	"github.com/stretchr/testify/assert"
-	"os"
-	"testing"
 )
 
 func TestMakeSkill(t *testing.T) {
@@ -27,9 +33,8 @@ func TestMakeModels(t *testing.T) {
 		l2met.New(l, ""),
 	)
 	sb := newSkill()
-	newLambda(app, sb)
 
-	ms, err := createSkillModels(sb)
+	ms, err := createSkillModels(app, sb)
 	assert.NoError(t, err)
 
 	for _, m := range ms {
@@ -38,3 +43,49 @@ func TestMakeModels(t *testing.T) {
 		assert.NotEmpty(t, string(res))
 	}
 }
+
+func TestMakeModels_WithoutLambda(t *testing.T) {
+	app := alfalfa.NewApplication(log.Null, stats.Null)
+	sb := newSkill()
+
+	ms, err := createSkillModels(app, sb)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ms)
+
+	for locale, m := range ms {
+		intents := map[string]skill.ModelIntent{}
+		for _, i := range m.Model.Language.Intents {
+			intents[i.Name] = i
+		}
+		for _, name := range []string{
+			alexa.HelpIntent, alexa.CancelIntent, alexa.StopIntent, loca.DemoIntent, loca.AWSStatus,
+		} {
+			assert.Contains(t, intents, name, locale)
+		}
+		assert.ElementsMatch(t, []skill.ModelSlot{
+			{Name: loca.TypeAreaName, Type: loca.TypeArea},
+			{Name: loca.TypeRegionName, Type: loca.TypeRegion},
+		}, slotsWithoutSamples(intents[loca.AWSStatus].Slots), locale)
+
+		var types []string
+		for _, typ := range m.Model.Language.Types {
+			assert.NotEmpty(t, typ.Values, locale)
+			types = append(types, typ.Name)
+		}
+		assert.ElementsMatch(t, []string{loca.TypeArea, loca.TypeRegion}, types, locale)
+	}
+}
+
+func TestMakeModels_WithoutIntents(t *testing.T) {
+	_, err := alfalfa.CreateSkillModels(newSkill(), nil)
+
+	assert.Error(t, err)
+}
+
+func slotsWithoutSamples(slots []skill.ModelSlot) []skill.ModelSlot {
+	res := make([]skill.ModelSlot, 0, len(slots))
+	for _, s := range slots {
+		res = append(res, skill.ModelSlot{Name: s.Name, Type: s.Type})
+	}
+	return res
+}
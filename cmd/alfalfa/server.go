@@ -1,26 +1,114 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/server"
 	"github.com/hamba/cmd"
 	"github.com/hamba/pkg/log"
+	"github.com/hamba/pkg/stats"
 	"github.com/urfave/cli/v2"
 )
 
+// Server flag constants.
+const (
+	FlagTLSCert = "tls.cert"
+	FlagTLSKey  = "tls.key"
+	FlagVerify  = "verify"
+
+	FlagLocalesDir      = "locales.dir"
+	FlagLocalesInterval = "locales.interval"
+)
+
 func runServer(c *cli.Context) error {
+	start := time.Now()
+
 	ctx, err := cmd.NewContext(c)
 	if err != nil {
 		return err
 	}
 
-	_, err = newApplication(ctx)
+	if err := newStats(c, ctx); err != nil {
+		return err
+	}
+
+	app, err := newApplication(ctx)
 	if err != nil {
 		log.Fatal(ctx, err.Error())
 	}
+	stats.Timing(ctx, "Boot", time.Since(start), 1.0)
+
+	wctx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if dir := c.String(FlagLocalesDir); dir != "" {
+		reg, err := newLocaleRegistry(ctx, dir)
+		if err != nil {
+			log.Fatal(ctx, err)
+		}
+		loca.Registry = reg
+		log.Info(ctx, "Loaded locales", "dir", dir, "version", reg.Version())
+		go reg.Watch(wctx, c.Duration(FlagLocalesInterval))
+	}
+
+	pa, err := newPersistence(c)
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
+	l := newLambda(app, pa, c.StringSlice(FlagApplicationID)...)
+
+	ms, err := createSkillModels(app, newSkill())
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
+	for l, m := range ms {
+		log.Info(ctx, fmt.Sprintf("accepting locale '%s' invocation '%s'", l, m.Model.Language.Invocation))
+	}
+	defer ctx.Close() //nolint:errcheck
+
+	var opts []server.OptFunc
+	if c.Bool(FlagVerify) {
+		opts = append(opts, server.WithVerifier(alexa.NewVerifier()))
+	} else {
+		log.Info(ctx, "Request verification is disabled!")
+	}
+
+	srv, err := server.NewServer(":"+c.String(cmd.FlagPort), l, opts...)
+	if err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		cert, key := c.String(FlagTLSCert), c.String(FlagTLSKey)
+		log.Info(ctx, "Starting server", "addr", srv.Addr, "tls", cert != "")
+
+		if cert != "" {
+			errCh <- srv.ListenAndServeTLS(cert, key)
+			return
+		}
+		errCh <- srv.ListenAndServe()
+	}()
+	stats.Timing(ctx, "Ready", time.Since(start), 1.0)
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		log.Error(ctx, "server error: "+err.Error())
+		return err
+	case <-cmd.WaitForSignals():
+	}
 
-	// TODO!
-	// s := rpc.NewServer(app)
-	log.Info(ctx, "Starting lambda server")
-	// if err :=
+	sctx, cancel := context.WithTimeout(context.Background(), server.ShutdownTimeout)
+	defer cancel()
 
-	return nil
+	log.Info(ctx, "Shutting down server")
+	return srv.Shutdown(sctx)
 }
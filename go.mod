@@ -1,11 +1,11 @@
 module github.com/drpsychick/alexa-go-cloudformation-demo
 
-go 1.15
+go 1.16
 
 require (
 	bou.ke/monkey v1.0.2
+	github.com/BurntSushi/toml v1.2.1
 	github.com/aws/aws-lambda-go v1.26.0
-	github.com/drpsychick/go-alexa-lambda v0.0.0-20210918223615-34f0cc198097
 	github.com/hamba/cmd v1.5.2
 	github.com/hamba/logger v1.1.0
 	github.com/hamba/pkg v1.4.0
@@ -14,4 +14,5 @@ require (
 	github.com/json-iterator/go v1.1.12
 	github.com/stretchr/testify v1.7.0
 	github.com/urfave/cli/v2 v2.3.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -2,13 +2,15 @@
 package lambda
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	alfalfa "github.com/drpsychick/alexa-go-cloudformation-demo"
 	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
-	"github.com/drpsychick/go-alexa-lambda"
-	"github.com/drpsychick/go-alexa-lambda/l10n"
-	"github.com/drpsychick/go-alexa-lambda/skill"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/skill"
 	"github.com/hamba/pkg/log"
 	"github.com/hamba/pkg/stats"
 )
@@ -29,26 +31,97 @@ type Application interface {
 	AWSStatus(l l10n.LocaleInstance, area, region string) (alexa.Response, error)
 }
 
+// Config contains the optional services used by the handlers.
+type Config struct {
+	Progressive alexa.ProgressiveResponder
+}
+
+// OptFunc defines the functions to be passed to NewMux and Intents.
+type OptFunc func(cfg *Config)
+
+// WithProgressiveResponder lets slow intents, e.g. AWSStatus, tell the user to wait with a progressive response.
+func WithProgressiveResponder(p alexa.ProgressiveResponder) OptFunc {
+	return func(cfg *Config) {
+		cfg.Progressive = p
+	}
+}
+
+func newConfig(opts []OptFunc) Config {
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
 // NewMux returns a new handler for defined intents.
-func NewMux(app Application, sb *skill.SkillBuilder) alexa.Handler {
+//
+// The model of the skill is created from the same definitions, see alfalfa.CreateSkillModels.
+func NewMux(app Application, opts ...OptFunc) alexa.Handler {
 	mux := alexa.NewServerMux(app.Logger())
-	sb.WithModel()
 
 	mux.HandleRequestTypeFunc(alexa.TypeLaunchRequest, handleLaunch(app))
 	mux.HandleRequestTypeFunc(alexa.TypeCanFulfillIntentRequest, handleCanFulfillIntent)
 	mux.HandleRequestTypeFunc(alexa.TypeSessionEndedRequest, handleEnd(app))
 
-	// new approach:
-	mux.HandleIntent(alexa.HelpIntent, handleHelp(app, sb))
-	mux.HandleIntent(alexa.CancelIntent, handleStop(app, sb))
-	mux.HandleIntent(alexa.StopIntent, handleStop(app, sb))
-	mux.HandleIntent(loca.DemoIntent, handleSSMLResponse(app, sb))
-	mux.HandleIntent(loca.SaySomething, handleSaySomethingResponse(app, sb))
-	mux.HandleIntent(loca.AWSStatus, handleAWSStatus(app, sb))
+	for _, d := range Intents(app, opts...) {
+		d.Register(mux, nil)
+	}
 
 	return mux
 }
 
+// Intents returns the definitions of all intents of the skill.
+func Intents(app Application, opts ...OptFunc) []alexa.IntentDefinition {
+	cfg := newConfig(opts)
+
+	return []alexa.IntentDefinition{
+		{Name: alexa.HelpIntent, Handler: handleHelp(app)},
+		{Name: alexa.CancelIntent, Handler: handleStop(app)},
+		{Name: alexa.StopIntent, Handler: handleStop(app)},
+		{Name: loca.DemoIntent, Handler: handleSSMLResponse(app)},
+		{Name: loca.SaySomething, Handler: handleSaySomethingResponse(app)},
+		{
+			Name:       loca.AWSStatus,
+			Delegation: skill.DelegationSkillResponse,
+			Types:      []string{loca.TypeArea, loca.TypeRegion},
+			// slots missing in the request may come from the session or the favourite region,
+			// the handler completes them before eliciting what is still missing
+			Slots: []alexa.SlotDefinition{
+				{
+					IntentSlot: alexa.IntentSlot{
+						Name: loca.TypeAreaName, Validate: true,
+						Elicit: handleAWSStatus(app, cfg.Progressive),
+					},
+					Type: loca.TypeArea,
+					// a confirmation prompt "breaks" `ask dialog --replay` as alexa asks the user to validate the input
+				},
+				{
+					IntentSlot: alexa.IntentSlot{
+						Name: loca.TypeRegionName, Validate: true,
+						Elicit: handleAWSStatus(app, cfg.Progressive),
+					},
+					Type: loca.TypeRegion,
+					// the prompt is part of the Alexa dialog, elicitation is not required as
+					// it "breaks" `ask dialog --replay` when starting the intent without any slots
+					Prompts: []alexa.SlotPrompt{
+						{Type: alexa.PromptElicitation, Variations: []string{"PlainText", "SSML"}},
+						{
+							Type: alexa.PromptValidation, Validation: skill.ValidationTypeHasMatch,
+							Variations: []string{"PlainText"},
+						},
+						{
+							Type: alexa.PromptValidation, Validation: skill.ValidationTypeInSet,
+							ValuesKey: loca.TypeRegionValues, Variations: []string{"PlainText"},
+						},
+					},
+				},
+			},
+			Handler: handleAWSStatus(app, cfg.Progressive),
+		},
+	}
+}
+
 func handleCanFulfillIntent(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
 	intent := r.Request.Intent.Name
 	if intent == loca.DemoIntent || intent == loca.SaySomething || intent == loca.AWSStatus {
@@ -79,8 +152,8 @@ func launch(app Application, b *alexa.ResponseBuilder, loc l10n.LocaleInstance)
 
 func handleLaunch(app Application) alexa.HandlerFunc {
 	return alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
-		var loc l10n.LocaleInstance
-		loc, err := loca.Registry.Resolve(r.RequestLocale())
+		state := alexa.NewSessionState(r)
+		loc, err := resolveLocale(r, state)
 		if err != nil {
 			if alexa.HandleError(b, loc, err) {
 				return
@@ -91,12 +164,14 @@ func handleLaunch(app Application) alexa.HandlerFunc {
 
 		if err := launch(app, b, loc); err != nil {
 			log.Error(app, "could not handle Stop: "+err.Error())
+			monitorLocaleErrors(app, loc)
 			if alexa.HandleError(b, loc, err) {
 				return
 			}
 			alexa.HandleError(b, loc, &DefaultError{loc})
 			return
 		}
+		b.WithSessionState(state)
 	})
 }
 
@@ -114,6 +189,7 @@ func handleEnd(app Application) alexa.HandlerFunc {
 
 		if err := stop(app, b, loc); err != nil {
 			log.Error(app, "could not handle Stop: "+err.Error())
+			monitorLocaleErrors(app, loc)
 			if alexa.HandleError(b, loc, err) {
 				return
 			}
@@ -137,12 +213,10 @@ func help(app Application, b *alexa.ResponseBuilder, loc l10n.LocaleInstance) er
 }
 
 // handleHelp calls the app help method, it does not close the session.
-func handleHelp(app Application, sb *skill.SkillBuilder) alexa.Handler {
-	sb.Model().WithIntent(alexa.HelpIntent)
-
+func handleHelp(app Application) alexa.Handler {
 	return alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
-		var loc l10n.LocaleInstance
-		loc, err := loca.Registry.Resolve(r.RequestLocale())
+		state := alexa.NewSessionState(r)
+		loc, err := resolveLocale(r, state)
 		if err != nil {
 			if alexa.HandleError(b, loc, err) {
 				return
@@ -153,12 +227,14 @@ func handleHelp(app Application, sb *skill.SkillBuilder) alexa.Handler {
 
 		if err := help(app, b, loc); err != nil {
 			log.Error(app, "could not handle Stop: "+err.Error())
+			monitorLocaleErrors(app, loc)
 			if alexa.HandleError(b, loc, err) {
 				return
 			}
 			alexa.HandleError(b, loc, &DefaultError{loc})
 			return
 		}
+		b.WithSessionState(state)
 	})
 }
 
@@ -176,10 +252,7 @@ func stop(app Application, b *alexa.ResponseBuilder, loc l10n.LocaleInstance) er
 	return nil
 }
 
-func handleStop(app Application, sb *skill.SkillBuilder) alexa.Handler {
-	sb.Model().WithIntent(alexa.StopIntent)
-	sb.Model().WithIntent(alexa.CancelIntent)
-
+func handleStop(app Application) alexa.Handler {
 	return alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
 		var loc l10n.LocaleInstance
 		loc, err := loca.Registry.Resolve(r.RequestLocale())
@@ -193,6 +266,7 @@ func handleStop(app Application, sb *skill.SkillBuilder) alexa.Handler {
 
 		if err := stop(app, b, loc); err != nil {
 			log.Error(app, "could not handle Stop: "+err.Error())
+			monitorLocaleErrors(app, loc)
 			if alexa.HandleError(b, loc, err) {
 				return
 			}
@@ -215,12 +289,10 @@ func ssmlResponse(app Application, b *alexa.ResponseBuilder, loc l10n.LocaleInst
 	return nil
 }
 
-func handleSSMLResponse(app Application, sb *skill.SkillBuilder) alexa.Handler {
-	sb.Model().WithIntent(loca.DemoIntent)
-
+func handleSSMLResponse(app Application) alexa.Handler {
 	return alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
-		var loc l10n.LocaleInstance
-		loc, err := loca.Registry.Resolve(r.RequestLocale())
+		state := alexa.NewSessionState(r)
+		loc, err := resolveLocale(r, state)
 		if err != nil {
 			if alexa.HandleError(b, loc, err) {
 				return
@@ -239,13 +311,12 @@ func handleSSMLResponse(app Application, sb *skill.SkillBuilder) alexa.Handler {
 			alexa.HandleError(b, loc, &DefaultError{loc})
 			return
 		}
+		b.WithSessionState(state)
 	})
 }
 
 // simple: one specific function per intent
-func handleSaySomethingResponse(app Application, sb *skill.SkillBuilder) alexa.Handler {
-	sb.Model().WithIntent(loca.SaySomething)
-
+func handleSaySomethingResponse(app Application) alexa.Handler {
 	return alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
 		var resp alexa.Response
 		var loc l10n.LocaleInstance
@@ -272,89 +343,126 @@ func handleSaySomethingResponse(app Application, sb *skill.SkillBuilder) alexa.H
 	})
 }
 
-func awsStatus(app Application, b *alexa.ResponseBuilder, loc l10n.LocaleInstance, r *alexa.RequestEnvelope) error { //nolint:funlen,gocognit,lll,cyclop
-	tags := []string{"intent", loca.AWSStatus, "locale", r.RequestLocale()}
-	var resp alexa.Response
-
-	// TODO: if err := validateSlot(loca.TypeAreaSlot); err != nil {}
-	slotArea, err := r.Slot(loca.TypeAreaName)
-	_, err2 := slotArea.FirstAuthorityWithMatch()
-	// elicit the slot value through Alexa
-	if err != nil || err2 != nil { //nolint:nestif
-		// failed validation or missing -> elicit - but need to provide prompt!
-		resp, err = app.AWSStatusAreaElicit(loc, r.SlotValue(loca.TypeAreaName))
-		if err != nil {
-			if alexa.HandleError(b, loc, err) {
-				return nil
-			}
-			return err
+// variantHistory is the number of variants of a key not repeated within a session.
+const variantHistory = 2
+
+// resolveLocale returns the request-scoped locale, which avoids the variants used last in the session.
+//
+// The state must be sent back with the response for the session to remember them.
+func resolveLocale(r *alexa.RequestEnvelope, state *alexa.SessionState) (l10n.LocaleInstance, error) {
+	loc, err := loca.Registry.Resolve(r.RequestLocale())
+	if err != nil {
+		return loc, err
+	}
+	return state.WithVariantHistory(loc, variantHistory), nil
+}
+
+// keyFavouriteRegion is the persistent attribute remembering the last area and region a user asked for.
+const keyFavouriteRegion = "favouriteRegion"
+
+// awsStatusState keeps the resolved slots of AWSStatus across turns.
+type awsStatusState struct {
+	Area   string `json:"area,omitempty"`
+	Region string `json:"region,omitempty"`
+}
+
+// awsStatusIntent returns the AWSStatus intent of the request, with the slots resolved so far.
+func awsStatusIntent(r *alexa.RequestEnvelope, st awsStatusState) *alexa.Intent {
+	intent := &alexa.Intent{
+		Name:               loca.AWSStatus,
+		ConfirmationStatus: alexa.ConfirmationStatusNone,
+		Slots:              map[string]*alexa.Slot{},
+	}
+	if i, err := r.Intent(); err == nil && i.ConfirmationStatus != "" {
+		intent.ConfirmationStatus = i.ConfirmationStatus
+	}
+
+	for name, value := range map[string]string{loca.TypeAreaName: st.Area, loca.TypeRegionName: st.Region} {
+		if value == "" {
+			continue
 		}
-		if err := alexa.CheckForLocaleError(loc); err != nil {
-			if alexa.HandleError(b, loc, err) {
-				return nil
-			}
-			return err
+		intent.Slots[name] = &alexa.Slot{
+			Name:               name,
+			Value:              value,
+			ConfirmationStatus: alexa.ConfirmationStatusNone,
 		}
-		b.With(resp)
-		return nil
-	}
-	area := slotArea.Value
-
-	// if slot is empty and dialog still open, respond with Dialog:Delegate
-	// if area == "" {
-	//	if r.Request.DialogState == alexa.DialogStateCompleted {
-	//		// should not happen (Alexa validation would have failed?)
-	//		// how to respond if it does happen?
-	//	} else {
-	//		b.AddDirective(&alexa.Directive{
-	//			Type: alexa.DirectiveTypeDialogDelegate,
-	//			// UpdatedIntent:  only needed when changing intent
-	//		})
-	//	}
-	//	return
-	// }
-
-	slotRegion, err := r.Slot(loca.TypeRegionName)
-	_, err2 = slotRegion.FirstAuthorityWithMatch()
-	// elicit the slot value through Alexa
-	if err != nil || err2 != nil { //nolint:nestif
-		// failed validation or missing -> elicit - but need to provide prompt!
-		resp, err = app.AWSStatusRegionElicit(loc, r.SlotValue(loca.TypeRegionName))
-		if err != nil {
-			if alexa.HandleError(b, loc, err) {
-				return nil
-			}
-			return err
+	}
+	return intent
+}
+
+// elicitSlot asks the user for the slot, keeping the slots resolved so far in the session and intent.
+func elicitSlot(
+	b *alexa.ResponseBuilder, loc l10n.LocaleInstance, r *alexa.RequestEnvelope,
+	slot string, st awsStatusState, state *alexa.SessionState, resp alexa.Response, err error,
+) error {
+	if err != nil {
+		if alexa.HandleError(b, loc, err) {
+			return nil
 		}
-		if err := alexa.CheckForLocaleError(loc); err != nil {
-			for _, e := range loc.GetErrors() {
-				log.Error(app, e.Error())
-			}
-			if alexa.HandleError(b, loc, err) {
-				return nil
-			}
-			return err
+		return err
+	}
+	if err := alexa.CheckForLocaleError(loc); err != nil {
+		if alexa.HandleError(b, loc, err) {
+			return nil
 		}
-		b.With(resp)
-		return nil
-	}
-	region := slotRegion.Value
-
-	// if slot is empty and dialog still open, respond with Dialog:Delegate
-	// if region == "" {
-	//	if r.Request.DialogState == alexa.DialogStateCompleted {
-	//		// should not happen (Alexa validation would have failed?)
-	//		// how to respond if it does happen?
-	//	} else {
-	//		b.AddDirective(&alexa.Directive{
-	//			Type: alexa.DirectiveTypeDialogDelegate,
-	//			// UpdatedIntent:  only needed when changing intent
-	//		})
-	//	}
-	//	return
-	// }
-
-	resp, err = app.AWSStatus(loc, area, region)
+		return err
+	}
+
+	// the question is spoken and repeated if the user does not answer
+	b.With(resp)
+	b.WithSpeech(resp.Speech)
+	b.WithDialogElicitSlot(slot, awsStatusIntent(r, st))
+	b.WithSessionState(state)
+	return nil
+}
+
+func awsStatus( //nolint:funlen,gocognit,cyclop
+	app Application, b *alexa.ResponseBuilder, loc l10n.LocaleInstance, r *alexa.RequestEnvelope,
+	state *alexa.SessionState, p alexa.ProgressiveResponder,
+) error {
+	tags := []string{"intent", loca.AWSStatus, "locale", r.RequestLocale()}
+
+	// slots resolved in previous turns survive in the session
+	var st awsStatusState
+	_ = state.Get(loca.AWSStatus, &st)
+
+	// the mux validated given slots, missing ones are completed from state or elicited
+	if v := r.ResolvedSlotValue(loca.TypeAreaName); v != "" {
+		st.Area = v
+	}
+	if v := r.ResolvedSlotValue(loca.TypeRegionName); v != "" {
+		st.Region = v
+	}
+
+	if err := state.Set(loca.AWSStatus, st); err != nil {
+		return err
+	}
+
+	// failed validation or missing -> elicit the slot value through Alexa
+	if st.Area == "" {
+		resp, err := app.AWSStatusAreaElicit(loc, r.SlotValue(loca.TypeAreaName))
+		return elicitSlot(b, loc, r, loca.TypeAreaName, st, state, resp, err)
+	}
+
+	// fall back to the favourite region of the user, unless asked for another area
+	persistent := b.PersistentState()
+	var favourite awsStatusState
+	_ = persistent.Get(keyFavouriteRegion, &favourite)
+	if st.Region == "" && st.Area == favourite.Area {
+		st.Region = favourite.Region
+	}
+
+	if st.Region == "" {
+		resp, err := app.AWSStatusRegionElicit(loc, r.SlotValue(loca.TypeRegionName))
+		return elicitSlot(b, loc, r, loca.TypeRegionName, st, state, resp, err)
+	}
+
+	// the lookup takes a while, the user should not wait in silence
+	if p != nil {
+		speakProgress(app, p, r, loc.GetAny(loca.AWSStatusProgressSSML))
+	}
+
+	resp, err := app.AWSStatus(loc, st.Area, st.Region)
 	if err != nil {
 		stats.Inc(app, "handleAWSStatus.error", 1, 1.0, tags...)
 		if alexa.HandleError(b, loc, err) {
@@ -369,27 +477,22 @@ func awsStatus(app Application, b *alexa.ResponseBuilder, loc l10n.LocaleInstanc
 		return err
 	}
 
+	// the intent is complete, forget the slots and remember the region of the area
+	state.Delete(loca.AWSStatus)
+	if st != favourite {
+		if err := persistent.Set(keyFavouriteRegion, st); err != nil {
+			return err
+		}
+	}
 	b.With(resp)
+	b.WithSessionState(state)
 	return nil
 }
 
-func handleAWSStatus(app Application, sb *skill.SkillBuilder) alexa.Handler {
-	// TODO: the mux should know about slots and "pass" it to the handler via request
-	// register intent, slots, types with the model
-	sb.Model().WithIntent(loca.AWSStatus)
-	sb.Model().
-		WithType(loca.TypeArea).
-		WithType(loca.TypeRegion)
-
-	sb.Model().Intent(loca.AWSStatus).
-		WithDelegation(skill.DelegationSkillResponse).
-		WithSlot(loca.TypeAreaName, loca.TypeArea).
-		WithSlot(loca.TypeRegionName, loca.TypeRegion)
-
+func handleAWSStatus(app Application, p alexa.ProgressiveResponder) alexa.Handler {
 	return alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
-		// var resp alexa.Response
-		var loc l10n.LocaleInstance
-		loc, err := loca.Registry.Resolve(r.RequestLocale())
+		state := alexa.NewSessionState(r)
+		loc, err := resolveLocale(r, state)
 		if err != nil {
 			if alexa.HandleError(b, loc, err) {
 				return
@@ -398,7 +501,7 @@ func handleAWSStatus(app Application, sb *skill.SkillBuilder) alexa.Handler {
 			return
 		}
 
-		if err := awsStatus(app, b, loc, r); err != nil {
+		if err := awsStatus(app, b, loc, r, state, p); err != nil {
 			// isResponse
 			log.Error(app, "could not handle AWSStatus: "+err.Error())
 			if alexa.HandleError(b, loc, err) {
@@ -412,6 +515,27 @@ func handleAWSStatus(app Application, sb *skill.SkillBuilder) alexa.Handler {
 	})
 }
 
+// progressiveTimeout limits the time spent on a progressive response, the actual response must follow within 8s.
+const progressiveTimeout = 2 * time.Second
+
+// speakProgress sends the progressive response, failures are logged as the response follows anyway.
+//
+// It gives up when the invocation is cancelled or after progressiveTimeout.
+func speakProgress(app Application, p alexa.ProgressiveResponder, r *alexa.RequestEnvelope, speech string) {
+	ctx, cancel := context.WithTimeout(r.InvocationContext(), progressiveTimeout)
+	defer cancel()
+
+	err := p.Speak(ctx, r, speech)
+	switch {
+	case errors.Is(err, alexa.ErrNoAPIAccess):
+		// e.g. requests of the simulator
+		log.Debug(app, "could not send progressive response: "+err.Error())
+	case err != nil:
+		log.Error(app, "could not send progressive response: "+err.Error())
+		stats.Inc(app, "progressive_response.error", 1, 1.0, "locale", r.RequestLocale())
+	}
+}
+
 // monitorLocaleErrors logs and stats every locale error.
 func monitorLocaleErrors(app Application, loc l10n.LocaleInstance) {
 	if len(loc.GetErrors()) > 0 {
@@ -1,22 +1,40 @@
 package lambda_test
 
 import (
+	"context"
 	"github.com/drpsychick/alexa-go-cloudformation-demo"
 	"github.com/drpsychick/alexa-go-cloudformation-demo/lambda"
 	"github.com/drpsychick/alexa-go-cloudformation-demo/lambda/middleware"
 	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
-	"github.com/drpsychick/go-alexa-lambda"
-	"github.com/drpsychick/go-alexa-lambda/l10n"
-	"github.com/drpsychick/go-alexa-lambda/skill"
-	"github.com/drpsychick/go-alexa-lambda/ssml"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/alexatest"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/ssml"
 	"github.com/hamba/pkg/log"
 	"github.com/hamba/pkg/stats"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
+	"net/http"
 	"testing"
+	"time"
 )
 
+// errorLogger records the messages of logged errors.
+type errorLogger struct {
+	errors []string
+}
+
+func (l *errorLogger) Debug(msg string, ctx ...interface{}) {}
+
+func (l *errorLogger) Info(msg string, ctx ...interface{}) {}
+
+func (l *errorLogger) Error(msg string, ctx ...interface{}) {
+	l.errors = append(l.errors, msg)
+}
+
 func initLocaleRegistry(t *testing.T) {
-	loca.Registry = l10n.NewRegistry()
+	// without default fallback, to cover unknown locales
+	loca.Registry = l10n.NewRegistry(l10n.WithoutDefaultFallback())
 	err := loca.Registry.Register(&l10n.Locale{Name: "en-US", TextSnippets: l10n.Snippets{}})
 	assert.NoError(t, err)
 	loc, err := loca.Registry.Resolve("en-US")
@@ -39,7 +57,8 @@ func initLocaleRegistry(t *testing.T) {
 func TestLambda_HandleLaunch(t *testing.T) {
 	initLocaleRegistry(t)
 
-	app := alfalfa.NewApplication(log.Null, stats.Null)
+	lg := &errorLogger{}
+	app := alfalfa.NewApplication(lg, stats.Null)
 	loc, err := loca.Registry.Resolve("en-US")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, loc)
@@ -52,9 +71,8 @@ func TestLambda_HandleLaunch(t *testing.T) {
 		},
 	}
 
-	sb := skill.NewSkillBuilder()
 	b := &alexa.ResponseBuilder{}
-	m := lambda.NewMux(app, sb)
+	m := lambda.NewMux(app)
 	m = middleware.WithRequestStats(m, app)
 
 	m.Serve(b, r)
@@ -71,10 +89,10 @@ func TestLambda_HandleLaunch(t *testing.T) {
 	resp = b.Build()
 
 	assert.NotEmpty(t, resp)
-	assert.NotEmpty(t, loc.GetErrors())
-	assert.Contains(t, loc.GetErrors()[0].Error(), l10n.KeyLaunchTitle)
+	// errors stay with the locale instance of the request and are logged
+	assert.Empty(t, loc.GetErrors())
+	assert.Contains(t, lg.errors, l10n.NoTranslationError{Locale: "en-US", Key: l10n.KeyLaunchTitle}.Error())
 	assert.Equal(t, "Translation error", resp.Response.Card.Title)
-	loc.ResetErrors()
 
 	// now with loca
 	loc.Set(l10n.KeyLaunchTitle, []string{"Start"})
@@ -92,10 +110,41 @@ func TestLambda_HandleLaunch(t *testing.T) {
 	assert.Equal(t, loc.Get(l10n.KeyLaunchText), resp.Response.Card.Content)
 }
 
+func TestLambda_HandleLaunch_VariantHistory(t *testing.T) {
+	initLocaleRegistry(t)
+	loc, err := loca.Registry.Resolve("en-US")
+	assert.NoError(t, err)
+	loc.Set(l10n.KeyLaunchTitle, []string{"Launch"})
+	loc.Set(l10n.KeyLaunchText, []string{"Hello"})
+	loc.Set(l10n.KeyLaunchSSML, []string{"<speak>Hi</speak>", "<speak>Hello</speak>"})
+
+	m := lambda.NewMux(alfalfa.NewApplication(log.Null, stats.Null))
+	r := &alexa.RequestEnvelope{
+		Session: &alexa.Session{},
+		Request: &alexa.Request{Type: alexa.TypeLaunchRequest, Locale: "en-US"},
+	}
+
+	var speech []string
+	for i := 0; i < 4; i++ {
+		b := &alexa.ResponseBuilder{}
+		m.Serve(b, r)
+		resp := b.Build()
+		speech = append(speech, resp.Response.OutputSpeech.SSML)
+		// Alexa sends the attributes back with the next request
+		r.Session.Attributes = resp.SessionAttributes
+	}
+
+	// the two variants alternate within the session
+	assert.NotEqual(t, speech[0], speech[1])
+	assert.Equal(t, speech[0], speech[2])
+	assert.Equal(t, speech[1], speech[3])
+}
+
 func TestLambda_HandleEnd(t *testing.T) {
 	initLocaleRegistry(t)
 
-	app := alfalfa.NewApplication(log.Null, stats.Null)
+	lg := &errorLogger{}
+	app := alfalfa.NewApplication(lg, stats.Null)
 
 	r := &alexa.RequestEnvelope{
 		Version: "1.0",
@@ -105,9 +154,8 @@ func TestLambda_HandleEnd(t *testing.T) {
 		},
 	}
 
-	sb := skill.NewSkillBuilder()
 	b := &alexa.ResponseBuilder{}
-	m := lambda.NewMux(app, sb)
+	m := lambda.NewMux(app)
 	m = middleware.WithRequestStats(m, app)
 
 	// missing locale
@@ -127,10 +175,10 @@ func TestLambda_HandleEnd(t *testing.T) {
 	resp = b.Build()
 
 	assert.NotEmpty(t, resp)
-	assert.NotEmpty(t, loc.GetErrors())
-	assert.Contains(t, loc.GetErrors()[0].Error(), l10n.KeyStopTitle)
+	// errors stay with the locale instance of the request and are logged
+	assert.Empty(t, loc.GetErrors())
+	assert.Contains(t, lg.errors, l10n.NoTranslationError{Locale: "en-US", Key: l10n.KeyStopTitle}.Error())
 	assert.Equal(t, "Translation error", resp.Response.Card.Title)
-	loc.ResetErrors()
 
 	// with translations
 	loc.Set(l10n.KeyStopTitle, []string{"Stop"})
@@ -148,7 +196,8 @@ func TestLambda_HandleEnd(t *testing.T) {
 func TestLambda_HandleHelp(t *testing.T) {
 	initLocaleRegistry(t)
 
-	app := alfalfa.NewApplication(log.Null, stats.Null)
+	lg := &errorLogger{}
+	app := alfalfa.NewApplication(lg, stats.Null)
 
 	r := &alexa.RequestEnvelope{
 		Version: "1.0",
@@ -161,9 +210,8 @@ func TestLambda_HandleHelp(t *testing.T) {
 		},
 	}
 
-	sb := skill.NewSkillBuilder()
 	b := &alexa.ResponseBuilder{}
-	m := lambda.NewMux(app, sb)
+	m := lambda.NewMux(app)
 	m = middleware.WithRequestStats(m, app)
 
 	// missing locale
@@ -182,10 +230,10 @@ func TestLambda_HandleHelp(t *testing.T) {
 	resp = b.Build()
 
 	assert.NotEmpty(t, resp)
-	assert.NotEmpty(t, loc.GetErrors())
-	assert.Contains(t, loc.GetErrors()[0].Error(), l10n.KeyHelpTitle)
+	// errors stay with the locale instance of the request and are logged
+	assert.Empty(t, loc.GetErrors())
+	assert.Contains(t, lg.errors, l10n.NoTranslationError{Locale: "en-US", Key: l10n.KeyHelpTitle}.Error())
 	assert.Equal(t, "Translation error", resp.Response.Card.Title)
-	loc.ResetErrors()
 
 	// with translations
 	loc.Set(l10n.KeyHelpTitle, []string{"Help"})
@@ -203,7 +251,8 @@ func TestLambda_HandleHelp(t *testing.T) {
 func TestLambda_HandleStop(t *testing.T) {
 	initLocaleRegistry(t)
 
-	app := alfalfa.NewApplication(log.Null, stats.Null)
+	lg := &errorLogger{}
+	app := alfalfa.NewApplication(lg, stats.Null)
 
 	r := &alexa.RequestEnvelope{
 		Version: "1.0",
@@ -216,9 +265,8 @@ func TestLambda_HandleStop(t *testing.T) {
 		},
 	}
 
-	sb := skill.NewSkillBuilder()
 	b := &alexa.ResponseBuilder{}
-	m := lambda.NewMux(app, sb)
+	m := lambda.NewMux(app)
 	m = middleware.WithRequestStats(m, app)
 
 	// missing locale
@@ -237,10 +285,10 @@ func TestLambda_HandleStop(t *testing.T) {
 	resp = b.Build()
 
 	assert.NotEmpty(t, resp)
-	assert.NotEmpty(t, loc.GetErrors())
+	// errors stay with the locale instance of the request and are logged
+	assert.Empty(t, loc.GetErrors())
+	assert.Contains(t, lg.errors, l10n.NoTranslationError{Locale: "en-US", Key: l10n.KeyStopTitle}.Error())
 	assert.Equal(t, "Translation error", resp.Response.Card.Title)
-	assert.Contains(t, loc.GetErrors()[0].Error(), l10n.KeyStopTitle)
-	loc.ResetErrors()
 
 	// with translations
 	loc.Set(l10n.KeyStopTitle, []string{"Stop"})
@@ -278,9 +326,8 @@ func TestLambda_HandleSaySomething2(t *testing.T) {
 			},
 		},
 	}
-	sb := skill.NewSkillBuilder()
 	b := &alexa.ResponseBuilder{}
-	m := lambda.NewMux(app, sb)
+	m := lambda.NewMux(app)
 	m = middleware.WithRequestStats(m, app)
 
 	m.Serve(b, r)
@@ -317,7 +364,8 @@ func TestLambda_HandleSaySomething2(t *testing.T) {
 func TestLambda_HandleAWSStatus(t *testing.T) {
 	initLocaleRegistry(t)
 
-	app := alfalfa.NewApplication(log.Null, stats.Null)
+	lg := &errorLogger{}
+	app := alfalfa.NewApplication(lg, stats.Null)
 
 	r := &alexa.RequestEnvelope{
 		Version: "1.0",
@@ -330,9 +378,8 @@ func TestLambda_HandleAWSStatus(t *testing.T) {
 		},
 	}
 
-	sb := skill.NewSkillBuilder()
 	b := &alexa.ResponseBuilder{}
-	m := lambda.NewMux(app, sb)
+	m := lambda.NewMux(app)
 	m = middleware.WithRequestStats(m, app)
 
 	// missing locale
@@ -351,10 +398,10 @@ func TestLambda_HandleAWSStatus(t *testing.T) {
 	resp = b.Build()
 
 	assert.NotEmpty(t, resp)
-	assert.NotEmpty(t, loc.GetErrors())
+	// errors stay with the locale instance of the request and are logged
+	assert.Empty(t, loc.GetErrors())
+	assert.Contains(t, lg.errors, l10n.NoTranslationError{Locale: "en-US", Key: loca.AWSStatusTitle}.Error())
 	assert.Equal(t, "Translation error", resp.Response.Card.Title)
-	assert.Contains(t, loc.GetErrors()[0].Error(), loca.AWSStatusTitle)
-	loc.ResetErrors()
 
 	// with translations
 	loc.Set(loca.AWSStatusTitle, []string{"Status"})
@@ -372,7 +419,8 @@ func TestLambda_HandleAWSStatus(t *testing.T) {
 func TestLambda_HandleAWSStatus_WithSlots(t *testing.T) {
 	initLocaleRegistry(t)
 
-	app := alfalfa.NewApplication(log.Null, stats.Null)
+	lg := &errorLogger{}
+	app := alfalfa.NewApplication(lg, stats.Null)
 
 	r := &alexa.RequestEnvelope{
 		Version: "1.0",
@@ -410,9 +458,8 @@ func TestLambda_HandleAWSStatus_WithSlots(t *testing.T) {
 		},
 	}
 
-	sb := skill.NewSkillBuilder()
 	b := &alexa.ResponseBuilder{}
-	m := lambda.NewMux(app, sb)
+	m := lambda.NewMux(app)
 	m = middleware.WithRequestStats(m, app)
 
 	// with translations
@@ -458,10 +505,10 @@ func TestLambda_HandleAWSStatus_WithSlots(t *testing.T) {
 	resp = b.Build()
 
 	assert.NotEmpty(t, resp)
-	assert.NotEmpty(t, loc.GetErrors())
+	// errors stay with the locale instance of the request and are logged
+	assert.Empty(t, loc.GetErrors())
+	assert.Contains(t, lg.errors, l10n.NoTranslationError{Locale: "en-US", Key: loca.AWSStatusText}.Error())
 	assert.Equal(t, "Translation error", resp.Response.Card.Title)
-	assert.Contains(t, loc.GetErrors()[0].Error(), loca.AWSStatusText)
-	loc.ResetErrors()
 
 	// with Region and loca
 	loc.Set(loca.AWSStatusText, []string{"Everything alright in %s %s"})
@@ -471,7 +518,315 @@ func TestLambda_HandleAWSStatus_WithSlots(t *testing.T) {
 	resp = b.Build()
 
 	assert.NotEmpty(t, resp)
-	assert.Empty(t, resp.Response.Card.Text)
+	assert.Empty(t, resp.Response.Card.Content)
+	assert.Equal(t, "Standard", resp.Response.Card.Type)
 	assert.Equal(t, loc.Get(loca.AWSStatusTitle), resp.Response.Card.Title)
-	assert.Equal(t, loc.Get(loca.AWSStatusText, "Europe", "Frankfurt"), resp.Response.Card.Content)
+	assert.Equal(t, loc.Get(loca.AWSStatusText, "Europe", "Frankfurt"), resp.Response.Card.Text)
+}
+
+func TestLambda_HandleAWSStatus_ProgressiveResponse(t *testing.T) {
+	initLocaleRegistry(t)
+	loc, err := loca.Registry.Resolve("en-US")
+	assert.NoError(t, err)
+	loc.Set(loca.AWSStatusTitle, []string{"Status"})
+	loc.Set(loca.AWSStatusText, []string{"Everything alright in %s %s"})
+	loc.Set(loca.AWSStatusSSML, []string{"<speak>All good in %s %s</speak>"})
+	loc.Set(loca.AWSStatusProgressSSML, []string{"<speak>Let me check...</speak>"})
+
+	srv := alexatest.NewDirectiveServer()
+	defer srv.Close()
+	app := alfalfa.NewApplication(log.Null, stats.Null)
+	m := lambda.NewMux(app, lambda.WithProgressiveResponder(alexa.NewProgressiveClient()))
+
+	match := func(name string) *alexa.Resolutions {
+		return &alexa.Resolutions{ResolutionsPerAuthority: []*alexa.PerAuthority{{
+			Status: &alexa.ResolutionStatus{Code: "ER_SUCCESS_MATCH"},
+			Values: []*alexa.AuthorityValue{{Value: &alexa.AuthorityValueValue{Name: name}}},
+		}}}
+	}
+	r := srv.WithAPI(&alexa.RequestEnvelope{
+		Version: "1.0",
+		Request: &alexa.Request{
+			RequestID: "request-id",
+			Locale:    "en-US",
+			Type:      alexa.TypeIntentRequest,
+			Intent: alexa.Intent{
+				Name: loca.AWSStatus,
+				Slots: map[string]*alexa.Slot{
+					loca.TypeAreaName:   {Name: loca.TypeAreaName, Value: "Europe", Resolutions: match("Europe")},
+					loca.TypeRegionName: {Name: loca.TypeRegionName, Value: "Frankfurt", Resolutions: match("Frankfurt")},
+				},
+			},
+		},
+	})
+
+	b := &alexa.ResponseBuilder{}
+	m.Serve(b, r)
+
+	assert.Equal(t, []alexatest.Directive{{
+		RequestID: "request-id",
+		Type:      alexa.DirectiveTypeVoicePlayerSpeak,
+		Speech:    "<speak>Let me check...</speak>",
+	}}, srv.Directives())
+	assert.Equal(t, "<speak>All good in Europe Frankfurt</speak>", b.Build().Response.OutputSpeech.SSML)
+
+	// a failing directive service does not fail the intent
+	srv.SetStatus(http.StatusInternalServerError)
+	b = &alexa.ResponseBuilder{}
+	m.Serve(b, r)
+
+	assert.Len(t, srv.Directives(), 2)
+	assert.Equal(t, "<speak>All good in Europe Frankfurt</speak>", b.Build().Response.OutputSpeech.SSML)
+}
+
+func TestLambda_HandleAWSStatus_ProgressiveResponseCancelled(t *testing.T) {
+	initLocaleRegistry(t)
+	loc, err := loca.Registry.Resolve("en-US")
+	assert.NoError(t, err)
+	loc.Set(loca.AWSStatusTitle, []string{"Status"})
+	loc.Set(loca.AWSStatusText, []string{"Everything alright in %s %s"})
+	loc.Set(loca.AWSStatusSSML, []string{"<speak>All good in %s %s</speak>"})
+	loc.Set(loca.AWSStatusProgressSSML, []string{"<speak>Let me check...</speak>"})
+
+	srv := alexatest.NewDirectiveServer()
+	defer srv.Close()
+	srv.Hang()
+	app := alfalfa.NewApplication(log.Null, stats.Null)
+	s := &alexa.Server{Handler: lambda.NewMux(app, lambda.WithProgressiveResponder(alexa.NewProgressiveClient()))}
+
+	match := func(name string) *alexa.Resolutions {
+		return &alexa.Resolutions{ResolutionsPerAuthority: []*alexa.PerAuthority{{
+			Status: &alexa.ResolutionStatus{Code: "ER_SUCCESS_MATCH"},
+			Values: []*alexa.AuthorityValue{{Value: &alexa.AuthorityValueValue{Name: name}}},
+		}}}
+	}
+	payload, err := jsoniter.Marshal(srv.WithAPI(&alexa.RequestEnvelope{
+		Version: "1.0",
+		Request: &alexa.Request{
+			RequestID: "request-id",
+			Locale:    "en-US",
+			Type:      alexa.TypeIntentRequest,
+			Intent: alexa.Intent{
+				Name: loca.AWSStatus,
+				Slots: map[string]*alexa.Slot{
+					loca.TypeAreaName:   {Name: loca.TypeAreaName, Value: "Europe", Resolutions: match("Europe")},
+					loca.TypeRegionName: {Name: loca.TypeRegionName, Value: "Frankfurt", Resolutions: match("Frankfurt")},
+				},
+			},
+		},
+	}))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	res, err := s.Invoke(ctx, payload)
+
+	assert.NoError(t, err)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.Len(t, srv.Directives(), 1)
+	assert.Contains(t, string(res), "All good in Europe Frankfurt")
+}
+
+func TestLambda_HandleAWSStatus_SessionState(t *testing.T) {
+	initLocaleRegistry(t)
+
+	app := alfalfa.NewApplication(log.Null, stats.Null)
+	loc, err := loca.Registry.Resolve("en-US")
+	assert.NoError(t, err)
+	loc.Set(loca.AWSStatusTitle, []string{"Status"})
+	loc.Set(loca.AWSStatusRegionElicitText, []string{"Elicit Region"})
+	loc.Set(loca.AWSStatusRegionElicitSSML, []string{"<speak>Elicit Region</speak>"})
+	loc.Set(loca.AWSStatusText, []string{"Everything alright in %s %s"})
+	loc.Set(loca.AWSStatusSSML, []string{"<speak>All good</speak>"})
+
+	match := func() *alexa.Resolutions {
+		return &alexa.Resolutions{
+			ResolutionsPerAuthority: []*alexa.PerAuthority{
+				{Status: &alexa.ResolutionStatus{Code: alexa.ResolutionStatusMatch}},
+			},
+		}
+	}
+
+	r := &alexa.RequestEnvelope{
+		Version: "1.0",
+		Session: &alexa.Session{},
+		Request: &alexa.Request{
+			Locale: "en-US",
+			Type:   alexa.TypeIntentRequest,
+			Intent: alexa.Intent{
+				Name: loca.AWSStatus,
+				Slots: map[string]*alexa.Slot{
+					loca.TypeAreaName: {Name: loca.TypeAreaName, Value: "Europe", Resolutions: match()},
+				},
+			},
+		},
+	}
+
+	m := lambda.NewMux(app)
+
+	// first turn: area given, region is elicited
+	b := &alexa.ResponseBuilder{}
+	m.Serve(b, r)
+	resp := b.Build()
+
+	assert.Equal(t, loc.Get(loca.AWSStatusRegionElicitText), resp.Response.Card.Content)
+	assert.NotEmpty(t, resp.SessionAttributes)
+	assert.Equal(t, alexa.DirectiveTypeDialogElicitSlot, resp.Response.Directives[0].Type)
+	assert.Equal(t, loca.TypeRegionName, resp.Response.Directives[0].SlotToElicit)
+	assert.Equal(t, "Europe", resp.Response.Directives[0].UpdatedIntent.Slots[loca.TypeAreaName].Value)
+	assert.NotContains(t, resp.Response.Directives[0].UpdatedIntent.Slots, loca.TypeRegionName)
+	assert.Equal(t, "<speak>Elicit Region</speak>", resp.Response.OutputSpeech.SSML)
+	assert.Equal(t, "<speak>Elicit Region</speak>", resp.Response.Reprompt.OutputSpeech.SSML)
+	assert.False(t, resp.Response.ShouldEndSession)
+
+	// second turn: only region given, area comes from the session
+	r.Session.Attributes = resp.SessionAttributes
+	r.Request.Intent.Slots = map[string]*alexa.Slot{
+		loca.TypeRegionName: {Name: loca.TypeRegionName, Value: "Frankfurt", Resolutions: match()},
+	}
+	b = &alexa.ResponseBuilder{}
+	m.Serve(b, r)
+	resp = b.Build()
+
+	assert.Equal(t, loc.Get(loca.AWSStatusText, "Europe", "Frankfurt"), resp.Response.Card.Text)
+	assert.Empty(t, resp.SessionAttributes)
+}
+
+func TestLambda_HandleAWSStatus_FavouriteRegion(t *testing.T) {
+	initLocaleRegistry(t)
+
+	app := alfalfa.NewApplication(log.Null, stats.Null)
+	loc, err := loca.Registry.Resolve("en-US")
+	assert.NoError(t, err)
+	loc.Set(loca.AWSStatusTitle, []string{"Status"})
+	loc.Set(loca.AWSStatusRegionElicitText, []string{"Elicit Region"})
+	loc.Set(loca.AWSStatusRegionElicitSSML, []string{"<speak>Elicit Region</speak>"})
+	loc.Set(loca.AWSStatusText, []string{"Everything alright in %s %s"})
+	loc.Set(loca.AWSStatusSSML, []string{"<speak>All good</speak>"})
+
+	match := &alexa.Resolutions{
+		ResolutionsPerAuthority: []*alexa.PerAuthority{
+			{Status: &alexa.ResolutionStatus{Code: alexa.ResolutionStatusMatch}},
+		},
+	}
+	r := &alexa.RequestEnvelope{
+		Version: "1.0",
+		Context: &alexa.Context{
+			System: &alexa.ContextSystem{User: &alexa.ContextUser{UserID: "user"}},
+		},
+		Request: &alexa.Request{
+			Locale: "en-US",
+			Type:   alexa.TypeIntentRequest,
+			Intent: alexa.Intent{
+				Name: loca.AWSStatus,
+				Slots: map[string]*alexa.Slot{
+					loca.TypeAreaName:   {Name: loca.TypeAreaName, Value: "Europe", Resolutions: match},
+					loca.TypeRegionName: {Name: loca.TypeRegionName, Value: "Ireland", Resolutions: match},
+				},
+			},
+		},
+	}
+
+	p := alexa.NewMemoryPersistence()
+	m := middleware.WithPersistence(lambda.NewMux(app), app, p)
+
+	b := &alexa.ResponseBuilder{}
+	m.Serve(b, r)
+	assert.Equal(t, loc.Get(loca.AWSStatusText, "Europe", "Ireland"), b.Build().Response.Card.Text)
+
+	// the region is remembered for the user
+	delete(r.Request.Intent.Slots, loca.TypeRegionName)
+	b = &alexa.ResponseBuilder{}
+	m.Serve(b, r)
+	assert.Equal(t, loc.Get(loca.AWSStatusText, "Europe", "Ireland"), b.Build().Response.Card.Text)
+
+	// but only for the same area
+	r.Request.Intent.Slots[loca.TypeAreaName].Value = "Asia Pacific"
+	b = &alexa.ResponseBuilder{}
+	m.Serve(b, r)
+	resp := b.Build()
+	assert.Equal(t, alexa.DirectiveTypeDialogElicitSlot, resp.Response.Directives[0].Type)
+	assert.Equal(t, loca.TypeRegionName, resp.Response.Directives[0].SlotToElicit)
+}
+
+func TestLambda_HandleAWSStatus_InvalidSlot(t *testing.T) {
+	initLocaleRegistry(t)
+
+	app := alfalfa.NewApplication(log.Null, stats.Null)
+	loc, err := loca.Registry.Resolve("en-US")
+	assert.NoError(t, err)
+	loc.Set(loca.AWSStatusTitle, []string{"Status"})
+	loc.Set(loca.AWSStatusRegionElicitText, []string{"Elicit Region"})
+	loc.Set(loca.AWSStatusRegionElicitSSML, []string{"<speak>Elicit Region</speak>"})
+
+	r := &alexa.RequestEnvelope{
+		Version: "1.0",
+		Request: &alexa.Request{
+			Locale: "en-US",
+			Type:   alexa.TypeIntentRequest,
+			Intent: alexa.Intent{
+				Name: loca.AWSStatus,
+				Slots: map[string]*alexa.Slot{
+					loca.TypeAreaName: {
+						Name:  loca.TypeAreaName,
+						Value: "Europe",
+						Resolutions: &alexa.Resolutions{
+							ResolutionsPerAuthority: []*alexa.PerAuthority{
+								{Status: &alexa.ResolutionStatus{Code: alexa.ResolutionStatusMatch}},
+							},
+						},
+					},
+					loca.TypeRegionName: {
+						Name:  loca.TypeRegionName,
+						Value: "franfrut",
+						Resolutions: &alexa.Resolutions{
+							ResolutionsPerAuthority: []*alexa.PerAuthority{
+								{Status: &alexa.ResolutionStatus{Code: alexa.ResolutionStatusNoMatch}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b := &alexa.ResponseBuilder{}
+	lambda.NewMux(app).Serve(b, r)
+	resp := b.Build()
+
+	assert.Equal(t, loc.Get(loca.AWSStatusRegionElicitText), resp.Response.Card.Content)
+	assert.Equal(t, alexa.DirectiveTypeDialogElicitSlot, resp.Response.Directives[0].Type)
+	assert.Equal(t, loca.TypeRegionName, resp.Response.Directives[0].SlotToElicit)
+	assert.Equal(t, "Europe", resp.Response.Directives[0].UpdatedIntent.Slots[loca.TypeAreaName].Value)
+	assert.NotContains(t, resp.Response.Directives[0].UpdatedIntent.Slots, loca.TypeRegionName)
+}
+
+func TestLambda_HandleAWSStatus_MissingArea(t *testing.T) {
+	initLocaleRegistry(t)
+
+	app := alfalfa.NewApplication(log.Null, stats.Null)
+	loc, err := loca.Registry.Resolve("en-US")
+	assert.NoError(t, err)
+	loc.Set(loca.AWSStatusTitle, []string{"Status"})
+	loc.Set(loca.AWSStatusAreaElicitText, []string{"Elicit Area"})
+	loc.Set(loca.AWSStatusAreaElicitSSML, []string{"<speak>Elicit Area</speak>"})
+
+	r := &alexa.RequestEnvelope{
+		Version: "1.0",
+		Request: &alexa.Request{
+			Locale: "en-US",
+			Type:   alexa.TypeIntentRequest,
+			Intent: alexa.Intent{Name: loca.AWSStatus},
+		},
+	}
+
+	b := &alexa.ResponseBuilder{}
+	lambda.NewMux(app).Serve(b, r)
+	resp := b.Build()
+
+	assert.Equal(t, loc.Get(loca.AWSStatusAreaElicitText), resp.Response.Card.Content)
+	assert.Len(t, resp.Response.Directives, 1)
+	assert.Equal(t, alexa.DirectiveTypeDialogElicitSlot, resp.Response.Directives[0].Type)
+	assert.Equal(t, loca.TypeAreaName, resp.Response.Directives[0].SlotToElicit)
 }
@@ -0,0 +1,41 @@
+// Package middleware for lambda requests
+package middleware
+
+import (
+	"errors"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/hamba/pkg/stats"
+)
+
+// WithApplicationID rejects requests which are not meant for one of the given application IDs.
+//
+// Rejected requests are not served, the server answers them with an error, see alexa.ErrRejected.
+// Without IDs, all requests are passed on to the handler.
+func WithApplicationID(h alexa.Handler, sable stats.Statable, ids ...string) alexa.Handler {
+	allowed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id != "" {
+			allowed[id] = true
+		}
+	}
+
+	return alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+		if len(allowed) == 0 {
+			h.Serve(b, r)
+			return
+		}
+
+		id, err := r.ApplicationID()
+		if err == nil && !allowed[id] {
+			err = errors.New("application id " + id + " not allowed")
+		}
+		if err != nil {
+			stats.Inc(sable, "request.rejected", 1, 1.0, "reason", "application_id")
+			b.Reject(err)
+			return
+		}
+
+		h.Serve(b, r)
+	})
+}
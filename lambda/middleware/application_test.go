@@ -0,0 +1,109 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/lambda/middleware"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/hamba/pkg/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithApplicationID(t *testing.T) {
+	served := 0
+	s := new(MockStats)
+	s.On("Inc", "request.rejected", int64(1), float32(1.0), []string{"reason", "application_id"})
+
+	m := middleware.WithApplicationID(alexa.HandlerFunc(
+		func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+			served++
+		}),
+		stats.NewMockStatable(s),
+		"my-skill",
+	)
+
+	// from session
+	req := &alexa.RequestEnvelope{
+		Session: &alexa.Session{
+			Application: &alexa.ContextApplication{ApplicationID: "my-skill"},
+		},
+	}
+	m.Serve(&alexa.ResponseBuilder{}, req)
+	assert.Equal(t, 1, served)
+
+	// from system
+	req = &alexa.RequestEnvelope{
+		Context: &alexa.Context{
+			System: &alexa.ContextSystem{
+				Application: &alexa.ContextApplication{ApplicationID: "my-skill"},
+			},
+		},
+	}
+	m.Serve(&alexa.ResponseBuilder{}, req)
+	assert.Equal(t, 2, served)
+
+	// unknown
+	req.Context.System.Application.ApplicationID = "other-skill"
+	bdr := &alexa.ResponseBuilder{}
+	m.Serve(bdr, req)
+	assert.Equal(t, 2, served)
+	assert.True(t, errors.Is(bdr.Rejected(), alexa.ErrRejected))
+
+	// missing
+	bdr = &alexa.ResponseBuilder{}
+	m.Serve(bdr, &alexa.RequestEnvelope{})
+	assert.Equal(t, 2, served)
+	assert.True(t, errors.Is(bdr.Rejected(), alexa.ErrRejected))
+
+	s.AssertNumberOfCalls(t, "Inc", 2)
+}
+
+func TestWithApplicationID_Server(t *testing.T) {
+	s := new(MockStats)
+	s.On("Inc", "request.rejected", int64(1), float32(1.0), []string{"reason", "application_id"})
+	srv := &alexa.Server{
+		Handler: middleware.WithApplicationID(alexa.HandlerFunc(
+			func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+				b.WithSpeech("hello")
+			}),
+			stats.NewMockStatable(s),
+			"my-skill",
+		),
+	}
+
+	body := `{"session":{"application":{"applicationId":"my-skill"}}}`
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "hello")
+
+	body = `{"session":{"application":{"applicationId":"other-skill"}}}`
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "hello")
+
+	resp, err := srv.Invoke(context.Background(), []byte(body))
+	assert.True(t, errors.Is(err, alexa.ErrRejected))
+	assert.Nil(t, resp)
+
+	s.AssertNumberOfCalls(t, "Inc", 2)
+}
+
+func TestWithApplicationID_NoIDs(t *testing.T) {
+	served := false
+	m := middleware.WithApplicationID(alexa.HandlerFunc(
+		func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+			served = true
+		}),
+		stats.NewMockStatable(new(MockStats)),
+	)
+
+	m.Serve(&alexa.ResponseBuilder{}, &alexa.RequestEnvelope{})
+	assert.True(t, served)
+}
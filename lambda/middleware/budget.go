@@ -0,0 +1,34 @@
+// Package middleware for lambda requests
+package middleware
+
+import (
+	"errors"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/ssml"
+	"github.com/hamba/pkg/stats"
+)
+
+// WithSpeechBudget measures the speech of responses against the budget, truncating it if set to.
+//
+// Responses over budget are counted as "response.speech.truncated" or "response.speech.over_budget",
+// responses with SSML that cannot be measured as "response.speech.invalid".
+func WithSpeechBudget(h alexa.Handler, sable stats.Statable, budget *ssml.Budget, truncate bool) alexa.Handler {
+	return alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+		b.WithSpeechBudget(budget, truncate)
+
+		h.Serve(b, r)
+
+		truncated, err := b.ApplyBudget()
+		tags := []string{"locale", r.RequestLocale()}
+		if truncated {
+			stats.Inc(sable, "response.speech.truncated", 1, 1.0, tags...)
+		}
+		switch {
+		case errors.Is(err, ssml.ErrBudget):
+			stats.Inc(sable, "response.speech.over_budget", 1, 1.0, tags...)
+		case err != nil:
+			stats.Inc(sable, "response.speech.invalid", 1, 1.0, tags...)
+		}
+	})
+}
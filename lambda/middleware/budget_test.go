@@ -0,0 +1,78 @@
+package middleware_test
+
+import (
+	"strings"
+	"testing"
+
+	alfalfa "github.com/drpsychick/alexa-go-cloudformation-demo"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/lambda"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/lambda/middleware"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/ssml"
+	"github.com/hamba/pkg/log"
+	"github.com/hamba/pkg/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSpeechBudget(t *testing.T) {
+	long := "<speak>" + strings.Repeat("Hello there. ", 1000) + "</speak>"
+	tests := []struct {
+		name     string
+		speech   string
+		truncate bool
+		stat     string
+		wantOver bool
+	}{
+		{"WithinBudget", "<speak>Hello</speak>", true, "", false},
+		{"Truncated", long, true, "response.speech.truncated", false},
+		{"OverBudget", long, false, "response.speech.over_budget", true},
+		{"Invalid", "<speak><p>Hello</speak>", true, "response.speech.invalid", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(MockStats)
+			if tt.stat != "" {
+				s.On("Inc", tt.stat, int64(1), float32(1.0), []string{"locale", "en-US"})
+			}
+			m := middleware.WithSpeechBudget(alexa.HandlerFunc(
+				func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+					b.WithSpeech(tt.speech)
+				}),
+				stats.NewMockStatable(s), ssml.NewBudget(), tt.truncate,
+			)
+
+			bdr := &alexa.ResponseBuilder{}
+			req := &alexa.RequestEnvelope{Request: &alexa.Request{Type: alexa.TypeLaunchRequest, Locale: "en-US"}}
+			m.Serve(bdr, req)
+
+			s.AssertExpectations(t)
+			speech := bdr.Build().Response.OutputSpeech.SSML
+			assert.Equal(t, tt.wantOver, len(speech) > ssml.MaxChars)
+		})
+	}
+}
+
+func TestWithSpeechBudget_Mux(t *testing.T) {
+	registry := loca.Registry
+	defer func() { loca.Registry = registry }()
+	loca.Registry = l10n.NewRegistry()
+	en := l10n.NewLocale("en-US")
+	en.Set(l10n.KeyLaunchTitle, []string{"Launch"})
+	en.Set(l10n.KeyLaunchText, []string{"Hello"})
+	en.Set(l10n.KeyLaunchSSML, []string{"<speak>" + strings.Repeat("Hello there. ", 1000) + "</speak>"})
+	assert.NoError(t, loca.Registry.Register(en, l10n.AsDefault()))
+
+	s := new(MockStats)
+	s.On("Inc", "response.speech.truncated", int64(1), float32(1.0), []string{"locale", "en-US"})
+	app := alfalfa.NewApplication(log.Null, stats.Null)
+	m := middleware.WithSpeechBudget(lambda.NewMux(app), stats.NewMockStatable(s), ssml.NewBudget(), true)
+
+	b := &alexa.ResponseBuilder{}
+	req := &alexa.RequestEnvelope{Request: &alexa.Request{Type: alexa.TypeLaunchRequest, Locale: "en-US"}}
+	m.Serve(b, req)
+
+	s.AssertExpectations(t)
+	assert.LessOrEqual(t, len(b.Build().Response.OutputSpeech.SSML), ssml.MaxChars)
+}
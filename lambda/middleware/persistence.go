@@ -0,0 +1,43 @@
+// Package middleware for lambda requests
+package middleware
+
+import (
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/hamba/pkg/log"
+	"github.com/hamba/pkg/stats"
+)
+
+// WithPersistence loads the persistent attributes before and saves them after the handler served the request.
+//
+// Requests without user or person are served without persistence, as are all requests if the adapter is nil.
+func WithPersistence(h alexa.Handler, app alexa.Application, a alexa.PersistenceAdapter) alexa.Handler {
+	return alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+		id := r.PersistenceID()
+		if a == nil || id == "" {
+			h.Serve(b, r)
+			return
+		}
+
+		attrs, err := a.Load(id)
+		if err != nil {
+			// serve without persistence rather than overwriting stored attributes
+			log.Error(app, "could not load persistent attributes: "+err.Error())
+			stats.Inc(app, "persistence.error", 1, 1.0, "op", "load")
+			h.Serve(b, r)
+			return
+		}
+
+		state := alexa.NewPersistentState(id, attrs)
+		b.WithPersistentState(state)
+
+		h.Serve(b, r)
+
+		if !state.Changed() {
+			return
+		}
+		if err := a.Save(id, state.Attributes()); err != nil {
+			log.Error(app, "could not save persistent attributes: "+err.Error())
+			stats.Inc(app, "persistence.error", 1, 1.0, "op", "save")
+		}
+	})
+}
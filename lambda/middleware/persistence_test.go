@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/lambda/middleware"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/hamba/pkg/log"
+	"github.com/hamba/pkg/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+type testApp struct {
+	log.Loggable
+	stats.Statable
+}
+
+type failingPersistence struct{}
+
+func (p failingPersistence) Load(id string) (map[string]interface{}, error) {
+	return nil, errors.New("load failed")
+}
+
+func (p failingPersistence) Save(id string, attrs map[string]interface{}) error {
+	return errors.New("save failed")
+}
+
+func TestWithPersistence(t *testing.T) {
+	app := testApp{log.NewMockLoggable(log.Null), stats.NewMockStatable(stats.Null)}
+	p := alexa.NewMemoryPersistence()
+
+	m := middleware.WithPersistence(alexa.HandlerFunc(
+		func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+			var count int
+			_ = b.PersistentState().Get("count", &count)
+			_ = b.PersistentState().Set("count", count+1)
+		}),
+		app,
+		p,
+	)
+
+	req := &alexa.RequestEnvelope{
+		Context: &alexa.Context{
+			System: &alexa.ContextSystem{
+				User: &alexa.ContextUser{UserID: "user"},
+			},
+		},
+	}
+	m.Serve(&alexa.ResponseBuilder{}, req)
+	m.Serve(&alexa.ResponseBuilder{}, req)
+
+	attrs, err := p.Load("user")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), attrs["count"])
+
+	// person is preferred over user
+	req.Context.System.Person = &alexa.ContextSystemPerson{PersonID: "person"}
+	m.Serve(&alexa.ResponseBuilder{}, req)
+
+	attrs, err = p.Load("person")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), attrs["count"])
+
+	// no user, no persistence
+	m.Serve(&alexa.ResponseBuilder{}, &alexa.RequestEnvelope{})
+}
+
+func TestWithPersistence_Errors(t *testing.T) {
+	s := new(MockStats)
+	s.On("Inc", "persistence.error", int64(1), float32(1.0), []string{"op", "load"})
+	app := testApp{log.NewMockLoggable(log.Null), stats.NewMockStatable(s)}
+
+	served := false
+	m := middleware.WithPersistence(alexa.HandlerFunc(
+		func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+			served = true
+		}),
+		app,
+		failingPersistence{},
+	)
+
+	req := &alexa.RequestEnvelope{
+		Context: &alexa.Context{
+			System: &alexa.ContextSystem{
+				User: &alexa.ContextUser{UserID: "user"},
+			},
+		},
+	}
+	m.Serve(&alexa.ResponseBuilder{}, req)
+
+	assert.True(t, served)
+	s.AssertExpectations(t)
+}
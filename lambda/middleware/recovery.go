@@ -4,7 +4,7 @@ package middleware
 import (
 	"fmt"
 
-	"github.com/drpsychick/go-alexa-lambda"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
 	"github.com/hamba/pkg/log"
 )
 
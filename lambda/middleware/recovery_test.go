@@ -5,7 +5,7 @@ import (
 	"testing"
 
 	"github.com/drpsychick/alexa-go-cloudformation-demo/lambda/middleware"
-	"github.com/drpsychick/go-alexa-lambda"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
 	"github.com/hamba/pkg/log"
 )
 
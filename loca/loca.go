@@ -2,7 +2,9 @@
 package loca
 
 import (
-	"github.com/drpsychick/go-alexa-lambda/l10n"
+	"embed"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
 )
 
 // keys of the project.
@@ -38,6 +40,7 @@ const (
 	AWSStatusRegionElicitText string = "AWSStatus_Region_Elicit_Text"
 	AWSStatusRegionElicitSSML string = "AWSStatus_Region_Elicit_SSML"
 	AWSStatusAreaConfirmSSML  string = "AWSStatus_Area_Confirm_SSML"
+	AWSStatusProgressSSML     string = "AWSStatus_Progress_SSML"
 	RegionValidateText        string = "_Region_Validate_Text"
 
 	// Types.
@@ -59,14 +62,16 @@ const (
 // Registry is the global l10n registry.
 var Registry = l10n.NewRegistry()
 
+// files contains one translation file per locale, named like the locale, e.g. "en-US.yaml".
+//
+//go:embed locales
+var files embed.FS
+
 func init() {
-	// default first
-	locales := []*l10n.Locale{
-		enUS, deDE, // frFR,
+	if err := l10n.RegisterFS(Registry, files, "locales"); err != nil {
+		panic("registration of locales failed: " + err.Error())
 	}
-	for _, l := range locales {
-		if err := Registry.Register(l); err != nil {
-			panic("registration of locale failed")
-		}
+	if err := Registry.SetDefault("en-US"); err != nil {
+		panic("default locale not found: " + err.Error())
 	}
 }
@@ -2,6 +2,7 @@ package loca_test
 
 import (
 	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -12,3 +13,14 @@ func TestL10NSetup(t *testing.T) {
 	assert.NotEmpty(t, l.GetName())
 	assert.Equal(t, "de-DE", l.GetName())
 }
+
+func TestL10NDefault(t *testing.T) {
+	l := loca.Registry.GetDefault()
+	assert.Equal(t, "en-US", l.GetName())
+	assert.NotEmpty(t, l.Get(loca.AWSStatusTitle))
+	assert.Empty(t, l.GetErrors())
+}
+
+func TestL10NLint(t *testing.T) {
+	assert.Empty(t, l10n.Lint(loca.Registry))
+}
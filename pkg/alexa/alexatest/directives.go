@@ -0,0 +1,128 @@
+// Package alexatest provides test doubles of the Alexa services.
+package alexatest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Token is the API access token the servers accept.
+const Token = "test-token"
+
+// Directive is a directive received by the DirectiveServer.
+type Directive struct {
+	RequestID string
+	Type      alexa.DirectiveType
+	Speech    string
+}
+
+// DirectiveServer is a test double of the directive service receiving progressive responses.
+type DirectiveServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	directives []Directive
+	status     int
+	hang       bool
+}
+
+// NewDirectiveServer starts a directive service, it must be closed when done.
+func NewDirectiveServer() *DirectiveServer {
+	s := &DirectiveServer{status: http.StatusNoContent}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *DirectiveServer) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/directives" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+Token {
+		writeError(w, http.StatusUnauthorized, "INVALID_AUTHENTICATION_TOKEN", "invalid token")
+		return
+	}
+
+	var req struct {
+		Header struct {
+			RequestID string `json:"requestId"`
+		} `json:"header"`
+		Directive struct {
+			Type   alexa.DirectiveType `json:"type"`
+			Speech string              `json:"speech"`
+		} `json:"directive"`
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := jsoniter.Unmarshal(body, &req); err != nil || req.Header.RequestID == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_DIRECTIVE", "invalid directive")
+		return
+	}
+
+	s.mu.Lock()
+	s.directives = append(s.directives, Directive{
+		RequestID: req.Header.RequestID,
+		Type:      req.Directive.Type,
+		Speech:    req.Directive.Speech,
+	})
+	status, hang := s.status, s.hang
+	s.mu.Unlock()
+
+	if hang {
+		<-r.Context().Done()
+		return
+	}
+
+	if status >= http.StatusMultipleChoices {
+		writeError(w, status, "INTERNAL_SERVICE_EXCEPTION", "failed")
+		return
+	}
+	w.WriteHeader(status)
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = jsoniter.NewEncoder(w).Encode(map[string]string{"code": code, "message": msg})
+}
+
+// SetStatus sets the status code of the responses, e.g. to fail with http.StatusInternalServerError.
+func (s *DirectiveServer) SetStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = status
+}
+
+// Hang lets the server receive directives without responding until the caller gives up.
+func (s *DirectiveServer) Hang() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hang = true
+}
+
+// Directives returns the directives received.
+func (s *DirectiveServer) Directives() []Directive {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Directive{}, s.directives...)
+}
+
+// WithAPI returns the request with the API endpoint and access token of the server.
+func (s *DirectiveServer) WithAPI(r *alexa.RequestEnvelope) *alexa.RequestEnvelope {
+	if r.Context == nil {
+		r.Context = &alexa.Context{}
+	}
+	if r.Context.System == nil {
+		r.Context.System = &alexa.ContextSystem{}
+	}
+	r.Context.System.APIEndpoint = s.URL
+	r.Context.System.APIAccessToken = Token
+	return r
+}
@@ -0,0 +1,136 @@
+package alexatest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// DeviceID is the device ID the ServicesServer serves.
+const DeviceID = "test-device"
+
+// ServicesServer is a test double of the settings, customer profile and device address services.
+//
+// The exported fields are served, they may be changed before the first call.
+type ServicesServer struct {
+	*httptest.Server
+
+	TimeZone        string
+	DistanceUnits   alexa.DistanceUnit
+	TemperatureUnit alexa.TemperatureUnit
+	Name            string
+	GivenName       string
+	Email           string
+	MobileNumber    alexa.PhoneNumber
+	Address         alexa.Address
+
+	mu     sync.Mutex
+	denied map[string]bool
+}
+
+// NewServicesServer starts the services with a user in Berlin who granted all permissions,
+// it must be closed when done.
+func NewServicesServer() *ServicesServer {
+	s := &ServicesServer{
+		TimeZone:        "Europe/Berlin",
+		DistanceUnits:   alexa.DistanceUnitMetric,
+		TemperatureUnit: alexa.TemperatureUnitCelsius,
+		Name:            "Max Mustermann",
+		GivenName:       "Max",
+		Email:           "max@example.com",
+		MobileNumber:    alexa.PhoneNumber{CountryCode: "+49", PhoneNumber: "1701234567"},
+		Address: alexa.Address{
+			AddressLine1: "Alexanderplatz 1",
+			City:         "Berlin",
+			PostalCode:   "10178",
+			CountryCode:  "DE",
+		},
+		denied: map[string]bool{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *ServicesServer) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+Token {
+		writeError(w, http.StatusUnauthorized, "INVALID_AUTHENTICATION_TOKEN", "invalid token")
+		return
+	}
+
+	var (
+		v          interface{}
+		permission string
+	)
+	settings := "/v2/devices/" + DeviceID + "/settings/"
+	address := "/v1/devices/" + DeviceID + "/settings/address"
+	switch path := r.URL.Path; {
+	case path == settings+"System.timeZone":
+		v = s.TimeZone
+	case path == settings+"System.distanceUnits":
+		v = s.DistanceUnits
+	case path == settings+"System.temperatureUnit":
+		v = s.TemperatureUnit
+	case path == "/v2/accounts/~current/settings/Profile.name":
+		v, permission = s.Name, alexa.PermissionProfileName
+	case path == "/v2/accounts/~current/settings/Profile.givenName":
+		v, permission = s.GivenName, alexa.PermissionProfileGivenName
+	case path == "/v2/accounts/~current/settings/Profile.email":
+		v, permission = s.Email, alexa.PermissionProfileEmail
+	case path == "/v2/accounts/~current/settings/Profile.mobileNumber":
+		v, permission = s.MobileNumber, alexa.PermissionProfileMobileNumber
+	case path == address:
+		v, permission = s.Address, alexa.PermissionDeviceAddress
+	case path == address+"/countryAndPostalCode":
+		v = alexa.Address{PostalCode: s.Address.PostalCode, CountryCode: s.Address.CountryCode}
+		permission = alexa.PermissionCountryAndPostalCode
+	case strings.HasPrefix(path, "/v1/devices/"), strings.HasPrefix(path, "/v2/devices/"):
+		writeError(w, http.StatusNotFound, "DEVICE_NOT_FOUND", "unknown device")
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	denied := s.denied[permission]
+	s.mu.Unlock()
+	if denied {
+		writeError(w, http.StatusForbidden, "ACCESS_DENIED", "the user has not granted the permission")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = jsoniter.NewEncoder(w).Encode(v)
+}
+
+// Deny revokes the permissions, calls needing them fail with http.StatusForbidden.
+func (s *ServicesServer) Deny(permissions ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range permissions {
+		s.denied[p] = true
+	}
+}
+
+// WithAPI returns the request with the API endpoint, access token and device ID of the server.
+func (s *ServicesServer) WithAPI(r *alexa.RequestEnvelope) *alexa.RequestEnvelope {
+	if r.Context == nil {
+		r.Context = &alexa.Context{}
+	}
+	if r.Context.System == nil {
+		r.Context.System = &alexa.ContextSystem{}
+	}
+	r.Context.System.APIEndpoint = s.URL
+	r.Context.System.APIAccessToken = Token
+	r.Context.System.Device.DeviceID = DeviceID
+	return r
+}
@@ -0,0 +1,167 @@
+package alexa
+
+// APL interface, document and datasource types.
+const (
+	// InterfaceAPL is the name of the APL interface in the supported interfaces of a device.
+	InterfaceAPL = "Alexa.Presentation.APL"
+	// APLVersion is the APL version of documents built with NewAPLBuilder.
+	APLVersion = "1.6"
+
+	APLDocumentTypeAPL  = "APL"
+	APLDocumentTypeLink = "Link"
+
+	APLDatasourceTypeObject = "object"
+)
+
+// APLDocument is an APL document, either inline or a link to a document saved in the authoring tool.
+// https://developer.amazon.com/en-US/docs/alexa/alexa-presentation-language/apl-document.html
+type APLDocument struct {
+	Type         string                 `json:"type"`
+	Version      string                 `json:"version,omitempty"`
+	Src          string                 `json:"src,omitempty"`
+	Theme        string                 `json:"theme,omitempty"`
+	Import       []APLImport            `json:"import,omitempty"`
+	Styles       map[string]interface{} `json:"styles,omitempty"`
+	MainTemplate *APLTemplate           `json:"mainTemplate,omitempty"`
+}
+
+// APLImport is a package imported by an APL document.
+type APLImport struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// APLTemplate is the main template of an APL document, its parameters are bound to the datasources.
+type APLTemplate struct {
+	Parameters []string       `json:"parameters,omitempty"`
+	Items      []APLComponent `json:"items,omitempty"`
+}
+
+// APLComponent is a component of an APL document with its properties, e.g. a Text or Container.
+type APLComponent map[string]interface{}
+
+// APLCommand is a command executed on an APL document with its properties, e.g. SpeakItem.
+type APLCommand map[string]interface{}
+
+// NewAPLComponent returns a component of the type with the properties.
+func NewAPLComponent(typ string, props map[string]interface{}) APLComponent {
+	c := APLComponent{"type": typ}
+	for k, v := range props {
+		c[k] = v
+	}
+	return c
+}
+
+// APLText returns a Text component.
+func APLText(text string) APLComponent {
+	return APLComponent{"type": "Text", "text": text}
+}
+
+// APLImage returns an Image component.
+func APLImage(src string) APLComponent {
+	return APLComponent{"type": "Image", "source": src}
+}
+
+// APLContainer returns a Container component of the items.
+func APLContainer(items ...APLComponent) APLComponent {
+	return APLComponent{"type": "Container", "items": items}
+}
+
+// NewAPLCommand returns a command of the type with the properties.
+func NewAPLCommand(typ string, props map[string]interface{}) APLCommand {
+	c := APLCommand{"type": typ}
+	for k, v := range props {
+		c[k] = v
+	}
+	return c
+}
+
+// APLLinkDocument returns a link to a document saved in the authoring tool, e.g. "doc://alexa/apl/documents/Demo".
+func APLLinkDocument(src string) *APLDocument {
+	return &APLDocument{Type: APLDocumentTypeLink, Src: src}
+}
+
+// APLBuilder builds an APL document and its datasources.
+type APLBuilder struct {
+	doc         *APLDocument
+	datasources map[string]interface{}
+}
+
+// NewAPLBuilder returns a builder of an inline APL document.
+func NewAPLBuilder() *APLBuilder {
+	return &APLBuilder{
+		doc: &APLDocument{
+			Type:         APLDocumentTypeAPL,
+			Version:      APLVersion,
+			MainTemplate: &APLTemplate{},
+		},
+		datasources: map[string]interface{}{},
+	}
+}
+
+// WithTheme sets the theme, "dark" or "light".
+func (b *APLBuilder) WithTheme(theme string) *APLBuilder {
+	b.doc.Theme = theme
+	return b
+}
+
+// WithImport imports a package, e.g. "alexa-layouts".
+func (b *APLBuilder) WithImport(name, version string) *APLBuilder {
+	b.doc.Import = append(b.doc.Import, APLImport{Name: name, Version: version})
+	return b
+}
+
+// WithItems adds the components to the main template.
+func (b *APLBuilder) WithItems(items ...APLComponent) *APLBuilder {
+	b.doc.MainTemplate.Items = append(b.doc.MainTemplate.Items, items...)
+	return b
+}
+
+// WithDatasource adds a datasource, bound to the main template parameter of the same name.
+func (b *APLBuilder) WithDatasource(name string, data interface{}) *APLBuilder {
+	if _, ok := b.datasources[name]; !ok {
+		b.doc.MainTemplate.Parameters = append(b.doc.MainTemplate.Parameters, name)
+	}
+	b.datasources[name] = data
+	return b
+}
+
+// WithObjectDatasource adds an object datasource with the properties, see WithDatasource.
+func (b *APLBuilder) WithObjectDatasource(name string, props map[string]interface{}) *APLBuilder {
+	return b.WithDatasource(name, map[string]interface{}{
+		"type":       APLDatasourceTypeObject,
+		"properties": props,
+	})
+}
+
+// Build returns the document and its datasources.
+func (b *APLBuilder) Build() (*APLDocument, map[string]interface{}) {
+	return b.doc, b.datasources
+}
+
+// SupportsInterface returns true if the device advertises the interface, e.g. InterfaceAPL.
+func (r *RequestEnvelope) SupportsInterface(name string) bool {
+	sys, err := r.System()
+	if err != nil {
+		return false
+	}
+	_, ok := sys.Device.SupportedInterfaces[name]
+	return ok
+}
+
+// SupportsAPL returns true if the device supports APL.
+func (r *RequestEnvelope) SupportsAPL() bool {
+	return r.SupportsInterface(InterfaceAPL)
+}
+
+// WithAPL renders the APL document only if the device of the request supports APL,
+// it returns true if the document was added.
+func (b *ResponseBuilder) WithAPL(
+	r *RequestEnvelope, token string, doc *APLDocument, datasources map[string]interface{},
+) bool {
+	if !r.SupportsAPL() {
+		return false
+	}
+	b.WithAPLRenderDocument(token, doc, datasources)
+	return true
+}
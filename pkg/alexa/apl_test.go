@@ -0,0 +1,49 @@
+package alexa
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPLBuilder(t *testing.T) {
+	doc, ds := NewAPLBuilder().
+		WithTheme("dark").
+		WithImport("alexa-layouts", "1.2.0").
+		WithItems(APLContainer(APLText("${payload.data.properties.title}"), APLImage("https://example.com/a.png"))).
+		WithObjectDatasource("payload", map[string]interface{}{"title": "Hello"}).
+		WithObjectDatasource("payload", map[string]interface{}{"title": "Hi"}).
+		Build()
+
+	json, err := jsoniter.MarshalToString(doc)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"APL","version":"1.6","theme":"dark",`+
+		`"import":[{"name":"alexa-layouts","version":"1.2.0"}],`+
+		`"mainTemplate":{"parameters":["payload"],"items":[{"items":[`+
+		`{"text":"${payload.data.properties.title}","type":"Text"},`+
+		`{"source":"https://example.com/a.png","type":"Image"}],"type":"Container"}]}}`, json)
+	json, err = jsoniter.MarshalToString(ds)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"payload":{"properties":{"title":"Hi"},"type":"object"}}`, json)
+}
+
+func TestResponseBuilder_WithAPL(t *testing.T) {
+	r := &RequestEnvelope{Request: &Request{}, Context: &Context{System: &ContextSystem{}}}
+	b := &ResponseBuilder{}
+
+	assert.False(t, b.WithAPL(r, "token", APLLinkDocument("doc://alexa/apl/documents/Demo"), nil))
+	assert.Empty(t, b.Build().Response.Directives)
+
+	r.Context.System.Device.SupportedInterfaces = map[string]struct{}{InterfaceAPL: {}}
+	assert.True(t, b.WithAPL(r, "token", APLLinkDocument("doc://alexa/apl/documents/Demo"), nil))
+	b.WithAPLExecuteCommands("token", NewAPLCommand("SpeakItem", map[string]interface{}{"componentId": "title"}))
+
+	json, err := jsoniter.MarshalToString(b.Build().Response.Directives)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"type":"Alexa.Presentation.APL.RenderDocument","token":"token",`+
+		`"document":{"type":"Link","src":"doc://alexa/apl/documents/Demo"}},`+
+		`{"type":"Alexa.Presentation.APL.ExecuteCommands","token":"token",`+
+		`"commands":[{"componentId":"title","type":"SpeakItem"}]}]`, json)
+	assert.False(t, (&RequestEnvelope{}).SupportsAPL())
+}
@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/drpsychick/go-alexa-lambda/l10n"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
 	"github.com/hamba/pkg/log"
 	"github.com/hamba/pkg/stats"
 )
@@ -17,11 +17,13 @@ type Application interface {
 }
 
 // Response wraps the data needed for a skill response.
+//
+// With Reprompt, the speech is used to reprompt the user instead of being spoken.
 type Response struct {
 	Title    string
 	Text     string
 	Speech   string
-	Image    string
+	Image    *Image
 	Reprompt bool
 	End      bool
 }
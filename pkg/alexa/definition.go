@@ -0,0 +1,121 @@
+package alexa
+
+import (
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/skill"
+)
+
+// Prompt types of a slot definition.
+const (
+	PromptElicitation  = "Elicitation"
+	PromptConfirmation = "Confirmation"
+	PromptValidation   = "Validation"
+)
+
+// SlotPrompt defines a dialog prompt of a slot in the interaction model.
+type SlotPrompt struct {
+	Type string
+	// Validation is the validation type of a PromptValidation, e.g. skill.ValidationTypeHasMatch.
+	Validation string
+	// ValuesKey is the lookup key for the values of validation types that require them.
+	ValuesKey string
+	// Variations are the variation types of the prompt, "PlainText" and/or "SSML".
+	Variations []string
+}
+
+// SlotDefinition defines an intent slot for the mux and the interaction model.
+type SlotDefinition struct {
+	IntentSlot
+
+	// Type is the name of the slot type.
+	Type string
+	// Samples overwrites the lookup key of the slot samples.
+	Samples string
+	// Elicitation and Confirmation are applied after the prompts, so a prompt can exist without being required.
+	Elicitation  bool
+	Confirmation bool
+	Prompts      []SlotPrompt
+}
+
+// IntentDefinition defines an intent once, with everything the mux and the interaction model need.
+type IntentDefinition struct {
+	Name string
+	// Samples overwrites the lookup key of the intent samples.
+	Samples string
+	// Delegation is the dialog delegation strategy of the intent.
+	Delegation   string
+	Confirmation bool
+	// Types are the slot types the intent requires in the model.
+	Types   []string
+	Slots   []SlotDefinition
+	Handler Handler
+}
+
+// Register registers the handler with the mux and the intent with the model of the skill.
+//
+// Both mux and sb are optional, the skill gets a model if it has none.
+func (d IntentDefinition) Register(mux *ServeMux, sb *skill.SkillBuilder) {
+	if mux != nil && d.Handler != nil {
+		if len(d.Slots) == 0 {
+			mux.HandleIntent(d.Name, d.Handler)
+		} else {
+			slots := make([]IntentSlot, 0, len(d.Slots))
+			for _, s := range d.Slots {
+				slots = append(slots, s.IntentSlot)
+			}
+			mux.HandleIntentWithSlots(d.Name, d.Handler, slots...)
+		}
+	}
+
+	if sb != nil {
+		d.registerModel(sb)
+	}
+}
+
+func (d IntentDefinition) registerModel(sb *skill.SkillBuilder) {
+	m := sb.WithModel().Model()
+	for _, t := range d.Types {
+		if m.Type(t) == nil {
+			m.WithType(t)
+		}
+	}
+
+	i := m.WithIntent(d.Name).Intent(d.Name)
+	if d.Samples != "" {
+		i.WithSamples(d.Samples)
+	}
+	if d.Delegation != "" {
+		i.WithDelegation(d.Delegation)
+	}
+	i.WithConfirmation(d.Confirmation)
+
+	for _, s := range d.Slots {
+		sl := i.WithSlot(s.Name, s.Type).Slot(s.Name)
+		if s.Samples != "" {
+			sl.WithSamples(s.Samples)
+		}
+
+		for _, p := range s.Prompts {
+			var pb *skill.ModelPromptBuilder
+			switch p.Type {
+			case PromptElicitation:
+				m.WithElicitationSlotPrompt(d.Name, s.Name)
+				pb = m.ElicitationPrompt(d.Name, s.Name)
+			case PromptConfirmation:
+				m.WithConfirmationSlotPrompt(d.Name, s.Name)
+				pb = m.ConfirmationPrompt(d.Name, s.Name)
+			case PromptValidation:
+				m.WithValidationSlotPrompt(s.Name, p.Validation, p.ValuesKey)
+				pb = m.ValidationPrompt(s.Name, p.Validation)
+			}
+			if pb == nil {
+				continue
+			}
+			for _, v := range p.Variations {
+				pb.WithVariation(v)
+			}
+		}
+
+		sl.WithElicitation(s.Elicitation).
+			WithConfirmation(s.Confirmation)
+	}
+}
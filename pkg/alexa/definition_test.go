@@ -0,0 +1,64 @@
+package alexa
+
+import (
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/skill"
+	"github.com/hamba/pkg/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntentDefinition_Register(t *testing.T) {
+	reg := l10n.NewRegistry()
+	assert.NoError(t, reg.Register(l10n.NewLocale("en-US")))
+	loc, err := reg.Resolve("en-US")
+	assert.NoError(t, err)
+	loc.Set(l10n.KeySkillInvocation, []string{"demo"})
+	loc.Set("Intent_Samples", []string{"do it"})
+	loc.Set("Type_Values", []string{"one", "two"})
+	loc.Set("Intent_Slot_Elicit_Text", []string{"which one?"})
+
+	d := IntentDefinition{
+		Name:       "Intent",
+		Delegation: skill.DelegationSkillResponse,
+		Types:      []string{"Type"},
+		Slots: []SlotDefinition{{
+			IntentSlot: IntentSlot{Name: "Slot", Optional: true},
+			Type:       "Type",
+			Prompts: []SlotPrompt{
+				{Type: PromptElicitation, Variations: []string{"PlainText"}},
+			},
+		}},
+		Handler: HandlerFunc(func(b *ResponseBuilder, r *RequestEnvelope) {
+			b.WithSimpleCard("intent", "")
+		}),
+	}
+
+	// the model is registered no matter the order
+	sb := skill.NewSkillBuilder().WithLocaleRegistry(reg)
+	mux := NewServerMux(log.Null)
+	d.Register(mux, sb)
+	sb.WithModel()
+
+	ms, err := sb.BuildModels()
+	assert.NoError(t, err)
+	m := ms["en-US"].Model
+
+	assert.Equal(t, "demo", m.Language.Invocation)
+	assert.Len(t, m.Language.Intents, 1)
+	assert.Equal(t, []string{"do it"}, m.Language.Intents[0].Samples)
+	assert.Equal(t, "Slot", m.Language.Intents[0].Slots[0].Name)
+	assert.Equal(t, "Type", m.Language.Intents[0].Slots[0].Type)
+	assert.Equal(t, "Type", m.Language.Types[0].Name)
+	assert.Len(t, m.Language.Types[0].Values, 2)
+	assert.Equal(t, skill.DelegationSkillResponse, m.Dialog.Intents[0].Delegation)
+	assert.False(t, m.Dialog.Intents[0].Slots[0].Elicitation)
+	assert.Equal(t, "Elicit.Intent-Intent.IntentSlot-Slot", m.Dialog.Intents[0].Slots[0].Prompts.Elicitation)
+	assert.Equal(t, "which one?", m.Prompts[0].Variations[0].Value)
+
+	// the mux serves the handler
+	b := &ResponseBuilder{}
+	mux.Serve(b, &RequestEnvelope{Request: &Request{Type: TypeIntentRequest, Intent: Intent{Name: "Intent"}}})
+	assert.Equal(t, "intent", b.Build().Response.Card.Title)
+}
@@ -0,0 +1,123 @@
+package l10n
+
+import (
+	"sort"
+	"strings"
+)
+
+// Fallbacks returns the names of the registered locales to use for the locale, best match first.
+//
+// The chain is: the locale itself, the locales registered as fallback for the locale or its
+// parent tags, the other locales of the same language and finally the default locale.
+// E.g. "de-AT" resolves to "de-AT", "de-DE", "en-US" with "de-DE" and "en-US" (default) registered.
+func (r *Registry) Fallbacks(locale string) []string {
+	var chain []string
+	add := func(name string) {
+		if _, ok := r.locales[name]; !ok {
+			return
+		}
+		for _, n := range chain {
+			if n == name {
+				return
+			}
+		}
+		chain = append(chain, name)
+	}
+
+	if name, ok := r.lookup(locale); ok {
+		add(name)
+	}
+
+	tag := canonicalTag(locale)
+	for _, t := range append([]string{tag}, parentTags(tag)...) {
+		if name, ok := r.fallbacks[t]; ok {
+			add(name)
+		}
+	}
+
+	names := make([]string, 0, len(r.locales))
+	for name := range r.locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if language(name) == language(tag) {
+			add(name)
+		}
+	}
+
+	if !r.noDefaultFallback {
+		add(r.defaultLocale)
+	}
+	return chain
+}
+
+// lookup returns the name of the registered locale matching the BCP-47 tag.
+func (r *Registry) lookup(locale string) (string, bool) {
+	if _, ok := r.locales[locale]; ok {
+		return locale, true
+	}
+
+	tag := canonicalTag(locale)
+	for name := range r.locales {
+		if canonicalTag(name) == tag {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// canonicalTag returns the BCP-47 tag in its canonical case, e.g. "de_at" -> "de-AT", "zh-hant-tw" -> "zh-Hant-TW".
+func canonicalTag(tag string) string {
+	parts := strings.FieldsFunc(tag, func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	for i, p := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(p)
+		case len(p) == 4:
+			parts[i] = strings.ToUpper(p[:1]) + strings.ToLower(p[1:])
+		case len(p) == 2 || len(p) == 3 && strings.Trim(p, "0123456789") == "":
+			parts[i] = strings.ToUpper(p)
+		default:
+			parts[i] = strings.ToLower(p)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// validTag returns true if the tag is a well-formed BCP-47 tag, e.g. "en", "de-AT" or "zh-Hant-TW".
+func validTag(tag string) bool {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+
+	parts := strings.Split(strings.ToLower(tag), "-")
+	if len(parts[0]) < 2 || len(parts[0]) > 3 || strings.Trim(parts[0], letters) != "" {
+		return false
+	}
+	for _, p := range parts[1:] {
+		if p == "" || len(p) > 8 || strings.Trim(p, letters+"0123456789") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parentTags returns the parents of the tag by truncation, e.g. "zh-Hant-TW" -> "zh-Hant", "zh".
+func parentTags(tag string) []string {
+	var tags []string
+	for i := strings.LastIndex(tag, "-"); i > 0; i = strings.LastIndex(tag, "-") {
+		tag = tag[:i]
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// language returns the language subtag of the locale.
+func language(locale string) string {
+	tag := canonicalTag(locale)
+	if i := strings.Index(tag, "-"); i > 0 {
+		return tag[:i]
+	}
+	return tag
+}
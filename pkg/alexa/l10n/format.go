@@ -0,0 +1,319 @@
+package l10n
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/ssml"
+)
+
+// FormatConfig contains the options for the Format functions.
+type FormatConfig struct {
+	SayAs bool
+}
+
+// FormatOptFunc defines the functions to be passed to the Format functions.
+type FormatOptFunc func(cfg *FormatConfig)
+
+// WithSayAs wraps the formatted value in the matching SSML say-as interpretation, if there is one.
+func WithSayAs() FormatOptFunc {
+	return func(cfg *FormatConfig) {
+		cfg.SayAs = true
+	}
+}
+
+func newFormatConfig(opts []FormatOptFunc) FormatConfig {
+	var cfg FormatConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// Time units of durations and relative times.
+const (
+	unitDay    = "day"
+	unitHour   = "hour"
+	unitMinute = "minute"
+	unitSecond = "second"
+)
+
+// localeFormat defines how to format values in a language or locale.
+type localeFormat struct {
+	decimal string
+	group   string
+	months  [12]string
+	// date formats day, month name and year.
+	date       func(day int, month string, year int) string
+	timeLayout string
+	ordinal    func(n int64) string
+	// units are the singular and plural of the time units, relUnits those used in relative times.
+	units    map[string][2]string
+	relUnits map[string][2]string
+	and      string
+	future   string
+	past     string
+	now      string
+}
+
+// localeFormats are the formats by language.
+var localeFormats = map[string]localeFormat{
+	"en": {
+		decimal: ".",
+		group:   ",",
+		months: [12]string{"January", "February", "March", "April", "May", "June", "July",
+			"August", "September", "October", "November", "December"},
+		date: func(day int, month string, year int) string {
+			return fmt.Sprintf("%s %d, %d", month, day, year)
+		},
+		timeLayout: "3:04 PM",
+		ordinal:    ordinalEnglish,
+		units: map[string][2]string{
+			unitDay: {"day", "days"}, unitHour: {"hour", "hours"},
+			unitMinute: {"minute", "minutes"}, unitSecond: {"second", "seconds"},
+		},
+		and:    "and",
+		future: "in %s",
+		past:   "%s ago",
+		now:    "now",
+	},
+	"de": {
+		decimal: ",",
+		group:   ".",
+		months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli",
+			"August", "September", "Oktober", "November", "Dezember"},
+		date: func(day int, month string, year int) string {
+			return fmt.Sprintf("%d. %s %d", day, month, year)
+		},
+		timeLayout: "15:04 Uhr",
+		ordinal: func(n int64) string {
+			return strconv.FormatInt(n, 10) + "."
+		},
+		units: map[string][2]string{
+			unitDay: {"Tag", "Tage"}, unitHour: {"Stunde", "Stunden"},
+			unitMinute: {"Minute", "Minuten"}, unitSecond: {"Sekunde", "Sekunden"},
+		},
+		// dative after "in" and "vor"
+		relUnits: map[string][2]string{
+			unitDay: {"Tag", "Tagen"}, unitHour: {"Stunde", "Stunden"},
+			unitMinute: {"Minute", "Minuten"}, unitSecond: {"Sekunde", "Sekunden"},
+		},
+		and:    "und",
+		future: "in %s",
+		past:   "vor %s",
+		now:    "jetzt",
+	},
+	"fr": {
+		decimal: ",",
+		group:   "\u202f",
+		months: [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet",
+			"août", "septembre", "octobre", "novembre", "décembre"},
+		date: func(day int, month string, year int) string {
+			if day == 1 {
+				return fmt.Sprintf("1er %s %d", month, year)
+			}
+			return fmt.Sprintf("%d %s %d", day, month, year)
+		},
+		timeLayout: "15 h 04",
+		ordinal: func(n int64) string {
+			if n == 1 {
+				return "1er"
+			}
+			return strconv.FormatInt(n, 10) + "e"
+		},
+		units: map[string][2]string{
+			unitDay: {"jour", "jours"}, unitHour: {"heure", "heures"},
+			unitMinute: {"minute", "minutes"}, unitSecond: {"seconde", "secondes"},
+		},
+		and:    "et",
+		future: "dans %s",
+		past:   "il y a %s",
+		now:    "maintenant",
+	},
+}
+
+// formatFor returns the format of the language of the locale, English if there is none.
+func formatFor(locale string) localeFormat {
+	if f, ok := localeFormats[language(locale)]; ok {
+		return f
+	}
+	return localeFormats["en"]
+}
+
+// sayAs returns the escaped text in the say-as element.
+func sayAs(interpretAs ssml.SayAsInterpretAs, text string) string {
+	return ssml.SayAs(interpretAs, "", ssml.Escape(text))
+}
+
+// FormatNumber returns the number with the decimal and group separators of the locale, e.g. "1.234,5" in German.
+//
+// With WithSayAs, the number is a cardinal without group separators.
+func FormatNumber(locale string, n float64, opts ...FormatOptFunc) string {
+	cfg := newFormatConfig(opts)
+	f := formatFor(locale)
+	if cfg.SayAs {
+		return sayAs(ssml.SayAsInterpretAsCardinal, formatDecimal(n, f.decimal, ""))
+	}
+	return formatDecimal(n, f.decimal, f.group)
+}
+
+func formatDecimal(n float64, decimal, group string) string {
+	s := strconv.FormatFloat(math.Abs(n), 'f', -1, 64)
+	if n == math.Trunc(n) && math.Abs(n) < 1e15 {
+		s = strconv.FormatInt(int64(math.Abs(n)), 10)
+	}
+
+	intPart, frac := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		intPart, frac = s[:i], s[i+1:]
+	}
+
+	var b strings.Builder
+	if n < 0 {
+		b.WriteString("-")
+	}
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(group)
+		}
+		b.WriteRune(c)
+	}
+	if frac != "" {
+		b.WriteString(decimal + frac)
+	}
+	return b.String()
+}
+
+// FormatOrdinal returns the ordinal number in the locale, e.g. "21st", "21." or "21e".
+//
+// With WithSayAs, the number is spoken as ordinal.
+func FormatOrdinal(locale string, n int64, opts ...FormatOptFunc) string {
+	cfg := newFormatConfig(opts)
+	if cfg.SayAs {
+		return sayAs(ssml.SayAsInterpretAsOrdinal, strconv.FormatInt(n, 10))
+	}
+	return formatFor(locale).ordinal(n)
+}
+
+func ordinalEnglish(n int64) string {
+	if n < 0 {
+		return "-" + ordinalEnglish(-n)
+	}
+
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.FormatInt(n, 10) + suffix
+}
+
+// FormatDate returns the date in the long format of the locale, e.g. "January 2, 2006" or "2. Januar 2006".
+//
+// With WithSayAs, the date is spoken as date by Alexa.
+func FormatDate(locale string, t time.Time, opts ...FormatOptFunc) string {
+	cfg := newFormatConfig(opts)
+	if cfg.SayAs {
+		return sayAs(ssml.SayAsInterpretAsDate, t.Format("20060102"))
+	}
+	f := formatFor(locale)
+	return f.date(t.Day(), f.months[t.Month()-1], t.Year())
+}
+
+// FormatTime returns the time of day in the locale, e.g. "3:04 PM" or "15:04 Uhr".
+func FormatTime(locale string, t time.Time) string {
+	return t.Format(formatFor(locale).timeLayout)
+}
+
+// FormatDuration returns the duration in words, rounded to seconds, e.g. "1 hour and 30 minutes".
+//
+// With WithSayAs, durations of less than an hour are spoken as time, e.g. 1'21".
+func FormatDuration(locale string, d time.Duration, opts ...FormatOptFunc) string {
+	cfg := newFormatConfig(opts)
+	d = d.Round(time.Second)
+	if d < 0 {
+		d = -d
+	}
+	if cfg.SayAs && d < time.Hour {
+		return sayAs(ssml.SayAsInterpretAsTime,
+			fmt.Sprintf(`%d'%d"`, int64(d/time.Minute), int64(d%time.Minute/time.Second)))
+	}
+
+	f := formatFor(locale)
+	parts := []struct {
+		unit string
+		n    int64
+	}{
+		{unitDay, int64(d / (24 * time.Hour))},
+		{unitHour, int64(d % (24 * time.Hour) / time.Hour)},
+		{unitMinute, int64(d % time.Hour / time.Minute)},
+		{unitSecond, int64(d % time.Minute / time.Second)},
+	}
+
+	var words []string
+	for _, p := range parts {
+		if p.n > 0 {
+			words = append(words, formatUnit(locale, f.units, p.unit, p.n))
+		}
+	}
+	switch len(words) {
+	case 0:
+		return formatUnit(locale, f.units, unitSecond, 0)
+	case 1:
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " " + f.and + " " + words[len(words)-1]
+}
+
+// FormatRelativeTime returns the duration relative to now in the largest unit, e.g. "in 5 minutes" or "vor 2 Tagen".
+//
+// Positive durations are in the future, negative ones in the past.
+func FormatRelativeTime(locale string, d time.Duration) string {
+	f := formatFor(locale)
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+
+	var unit string
+	var n int64
+	switch {
+	case abs < time.Second:
+		return f.now
+	case abs < time.Minute:
+		unit, n = unitSecond, int64(abs.Round(time.Second)/time.Second)
+	case abs < time.Hour:
+		unit, n = unitMinute, int64(abs.Round(time.Minute)/time.Minute)
+	case abs < 24*time.Hour:
+		unit, n = unitHour, int64(abs.Round(time.Hour)/time.Hour)
+	default:
+		unit, n = unitDay, int64(abs.Round(24*time.Hour)/(24*time.Hour))
+	}
+
+	units := f.relUnits
+	if units == nil {
+		units = f.units
+	}
+	s := formatUnit(locale, units, unit, n)
+	if d < 0 {
+		return fmt.Sprintf(f.past, s)
+	}
+	return fmt.Sprintf(f.future, s)
+}
+
+// formatUnit returns the number with the singular or plural of the unit.
+func formatUnit(locale string, units map[string][2]string, unit string, n int64) string {
+	word := units[unit][1]
+	if PluralCategory(locale, float64(n)) == PluralOne {
+		word = units[unit][0]
+	}
+	return FormatNumber(locale, float64(n)) + " " + word
+}
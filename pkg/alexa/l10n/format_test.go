@@ -0,0 +1,98 @@
+package l10n_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "1,234,567.5", l10n.FormatNumber("en-US", 1234567.5))
+	assert.Equal(t, "-1.234,5", l10n.FormatNumber("de-DE", -1234.5))
+	assert.Equal(t, "1\u202f234", l10n.FormatNumber("fr-FR", 1234))
+	assert.Equal(t, "999", l10n.FormatNumber("xx", 999))
+	assert.Equal(t, `<say-as interpret-as="cardinal">1234,5</say-as>`,
+		l10n.FormatNumber("de-DE", 1234.5, l10n.WithSayAs()))
+}
+
+func TestFormatOrdinal(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      int64
+		want   string
+	}{
+		{"en-US", 1, "1st"},
+		{"en-US", 2, "2nd"},
+		{"en-US", 3, "3rd"},
+		{"en-US", 11, "11th"},
+		{"en-US", 112, "112th"},
+		{"en-US", 21, "21st"},
+		{"de-DE", 21, "21."},
+		{"fr-FR", 1, "1er"},
+		{"fr-FR", 2, "2e"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, l10n.FormatOrdinal(tt.locale, tt.n))
+	}
+	assert.Equal(t, `<say-as interpret-as="ordinal">3</say-as>`, l10n.FormatOrdinal("en-US", 3, l10n.WithSayAs()))
+}
+
+func TestFormatDateTime(t *testing.T) {
+	d := time.Date(2021, time.March, 1, 15, 4, 0, 0, time.UTC)
+
+	assert.Equal(t, "March 1, 2021", l10n.FormatDate("en-US", d))
+	assert.Equal(t, "1. März 2021", l10n.FormatDate("de-DE", d))
+	assert.Equal(t, "1er mars 2021", l10n.FormatDate("fr-FR", d))
+	assert.Equal(t, `<say-as interpret-as="date">20210301</say-as>`, l10n.FormatDate("de-DE", d, l10n.WithSayAs()))
+
+	assert.Equal(t, "3:04 PM", l10n.FormatTime("en-US", d))
+	assert.Equal(t, "15:04 Uhr", l10n.FormatTime("de-DE", d))
+	assert.Equal(t, "15 h 04", l10n.FormatTime("fr-FR", d))
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		locale string
+		d      time.Duration
+		want   string
+	}{
+		{"en-US", 0, "0 seconds"},
+		{"en-US", time.Second, "1 second"},
+		{"en-US", 90 * time.Minute, "1 hour and 30 minutes"},
+		{"en-US", 26*time.Hour + 61*time.Second, "1 day, 2 hours, 1 minute and 1 second"},
+		{"de-DE", 90 * time.Minute, "1 Stunde und 30 Minuten"},
+		{"de-DE", 0, "0 Sekunden"},
+		{"fr-FR", 2*time.Hour + time.Minute, "2 heures et 1 minute"},
+		{"fr-FR", 0, "0 seconde"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, l10n.FormatDuration(tt.locale, tt.d))
+	}
+	assert.Equal(t, `<say-as interpret-as="time">1'21"</say-as>`,
+		l10n.FormatDuration("en-US", 81*time.Second, l10n.WithSayAs()))
+	assert.Equal(t, "2 hours", l10n.FormatDuration("en-US", 2*time.Hour, l10n.WithSayAs()))
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	tests := []struct {
+		locale string
+		d      time.Duration
+		want   string
+	}{
+		{"en-US", 0, "now"},
+		{"en-US", 5 * time.Minute, "in 5 minutes"},
+		{"en-US", -time.Hour, "1 hour ago"},
+		{"de-DE", -48 * time.Hour, "vor 2 Tagen"},
+		{"de-DE", 30 * time.Second, "in 30 Sekunden"},
+		{"fr-FR", -3 * time.Hour, "il y a 3 heures"},
+		{"fr-FR", 24 * time.Hour, "dans 1 jour"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, l10n.FormatRelativeTime(tt.locale, tt.d))
+	}
+}
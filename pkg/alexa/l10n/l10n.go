@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -149,6 +150,8 @@ type LocaleRegistry interface {
 }
 
 // LocaleInstance is the interface for a specific locale.
+//
+// The instances resolved from a registry are request-scoped: errors of one request do not leak into another.
 type LocaleInstance interface {
 	GetName() string
 	Set(key string, values []string)
@@ -159,6 +162,19 @@ type LocaleInstance interface {
 	ResetErrors()
 }
 
+// Scoper is implemented by locales that return request-scoped instances of themselves.
+type Scoper interface {
+	Scope() LocaleInstance
+}
+
+// scope returns a request-scoped instance of the locale, if it supports it.
+func scope(l LocaleInstance) LocaleInstance {
+	if s, ok := l.(Scoper); ok {
+		return s.Scope()
+	}
+	return l
+}
+
 // DefaultRegistry is the standard registry used.
 var DefaultRegistry = NewRegistry()
 
@@ -178,15 +194,58 @@ func AsDefault() RegisterFunc {
 	}
 }
 
+// AsFallbackFor registers the given Locale as the fallback for a language or locale, e.g. "de" or "de-AT".
+func AsFallbackFor(tag string) RegisterFunc {
+	return func(cfg *Config) {
+		cfg.FallbackFor = tag
+	}
+}
+
+// RegistryConfig contains the options for a Registry.
+type RegistryConfig struct {
+	NoDefaultFallback bool
+	Selector          Selector
+}
+
+// RegistryOptFunc defines the functions to be passed to NewRegistry.
+type RegistryOptFunc func(cfg *RegistryConfig)
+
+// WithoutDefaultFallback makes Resolve fail for locales without a registered locale of the same language.
+func WithoutDefaultFallback() RegistryOptFunc {
+	return func(cfg *RegistryConfig) {
+		cfg.NoDefaultFallback = true
+	}
+}
+
+// WithSelector sets the Selector of the locales resolved from the registry, see SelectorSetter.
+func WithSelector(s Selector) RegistryOptFunc {
+	return func(cfg *RegistryConfig) {
+		cfg.Selector = s
+	}
+}
+
 // Registry is the Locale registry.
 type Registry struct {
-	defaultLocale string
-	locales       map[string]LocaleInstance
+	defaultLocale     string
+	locales           map[string]LocaleInstance
+	fallbacks         map[string]string
+	noDefaultFallback bool
+	selector          Selector
 }
 
 // NewRegistry returns an empty Registry.
-func NewRegistry() LocaleRegistry {
-	return &Registry{locales: map[string]LocaleInstance{}}
+func NewRegistry(opts ...RegistryOptFunc) LocaleRegistry {
+	var cfg RegistryConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Registry{
+		locales:           map[string]LocaleInstance{},
+		fallbacks:         map[string]string{},
+		noDefaultFallback: cfg.NoDefaultFallback,
+		selector:          cfg.Selector,
+	}
 }
 
 // Register registers a new Locale in the DefaultRegistry.
@@ -233,45 +292,81 @@ func (r *Registry) Register(l LocaleInstance, opts ...RegisterFunc) error {
 	if cfg.DefaultLocale || r.defaultLocale == "" {
 		r.defaultLocale = l.GetName()
 	}
+	if cfg.FallbackFor != "" {
+		r.fallbacks[canonicalTag(cfg.FallbackFor)] = l.GetName()
+	}
 
 	r.locales[l.GetName()] = l
 
 	return nil
 }
 
-// GetDefault returns the default locale.
+// GetDefault returns a new instance of the default locale.
 func (r *Registry) GetDefault() LocaleInstance {
-	return r.locales[r.defaultLocale]
+	l, ok := r.locales[r.defaultLocale]
+	if !ok {
+		return nil
+	}
+	return r.scope(l)
 }
 
 // SetDefault sets the default locale which must be registered.
 func (r *Registry) SetDefault(locale string) error {
-	if _, err := r.Resolve(locale); err != nil {
-		return err
+	name, ok := r.lookup(locale)
+	if !ok {
+		return fmt.Errorf("locale '%s' not found", locale)
 	}
-	r.defaultLocale = locale
+	r.defaultLocale = name
 	return nil
 }
 
-// GetLocales returns all registered locales.
+// GetLocales returns all registered locales, they are shared by all requests.
 func (r *Registry) GetLocales() map[string]LocaleInstance {
 	return r.locales
 }
 
-// Resolve returns the Locale matching the given name or an error.
+// Resolve returns a new instance of the Locale matching the given name or an error.
+//
+// Without an exact match, it falls back to the locales of the same language and then the default,
+// see Fallbacks. Missing keys are looked up in the fallbacks of the same language.
 func (r *Registry) Resolve(locale string) (LocaleInstance, error) {
-	l, ok := r.locales[locale]
-	if !ok {
+	chain := r.Fallbacks(locale)
+	if len(chain) == 0 {
 		return nil, fmt.Errorf("locale '%s' not found", locale)
 	}
+
+	l := r.scope(r.locales[chain[0]])
+	if loc, ok := l.(*Locale); ok {
+		lang := language(chain[0])
+		for _, name := range chain[1:] {
+			if p, ok := r.locales[name].(*Locale); ok && language(name) == lang {
+				loc.parents = append(loc.parents, p)
+			}
+		}
+	}
 	return l, nil
 }
 
+// scope returns a request-scoped instance of the locale with the Selector of the registry.
+func (r *Registry) scope(l LocaleInstance) LocaleInstance {
+	l = scope(l)
+	if s, ok := l.(SelectorSetter); ok && r.selector != nil {
+		s.SetSelector(r.selector)
+	}
+	return l
+}
+
 // Locale is a representation of keys in a specific language.
+//
+// It is safe for concurrent use, except for Set which is meant to be used during setup only.
 type Locale struct {
 	Name         string // de-DE, en-US, ...
 	TextSnippets Snippets
-	errors       []error
+
+	parents  []*Locale
+	selector Selector
+	mu       sync.Mutex
+	errors   []error
 }
 
 // NewLocale creates a new, empty locale.
@@ -282,82 +377,117 @@ func NewLocale(locale string) *Locale {
 	}
 }
 
+// Scope returns a new instance sharing the snippets of the locale, with its own errors.
+func (l *Locale) Scope() LocaleInstance {
+	return &Locale{Name: l.Name, TextSnippets: l.TextSnippets, parents: l.parents, selector: l.selector}
+}
+
 // GetName returns the name of the locale.
 func (l *Locale) GetName() string {
 	return l.Name
 }
 
+// SetSelector sets the Selector used by GetAny, variants are selected at random if it is nil.
+func (l *Locale) SetSelector(s Selector) {
+	l.selector = s
+}
+
 // Set sets the translations for a key.
 func (l *Locale) Set(key string, values []string) {
 	l.TextSnippets[key] = values
 }
 
 // Get returns the first translation.
+//
+// The translation is formatted with fmt.Sprintf or, if the only argument are Args, as message template.
 func (l *Locale) Get(key string, args ...interface{}) string {
-	t, err := l.TextSnippets.GetFirst(key, args...)
-	if err != nil {
-		var locaerr NoTranslationError
-		switch {
-		case errors.As(err, &locaerr):
-			locaerr.Locale = l.GetName()
-			l.errors = append(l.errors, locaerr)
-		default:
-			l.errors = append(l.errors, err)
-		}
-	}
+	t, err := l.snippets(key).first(l.Name, key, args)
+	l.appendError(err)
 	l.appendErrorMissingParam(key, []string{t})
 	return t
 }
 
-// GetAny returns a random translation.
+// GetAny returns a random translation, or the one chosen by the Selector of the locale.
 func (l *Locale) GetAny(key string, args ...interface{}) string {
-	t, err := l.TextSnippets.GetAny(key, args...)
-	if err != nil {
-		var locaErr NoTranslationError
-		switch {
-		case errors.As(err, &locaErr):
-			locaErr.Locale = l.GetName()
-			l.errors = append(l.errors, locaErr)
-		default:
-			l.errors = append(l.errors, err)
-		}
-	}
+	t, err := l.snippets(key).any(l.selector, l.Name, key, args)
+	l.appendError(err)
 	l.appendErrorMissingParam(key, []string{t})
 	return t
 }
 
 // GetAll returns all translations.
 func (l *Locale) GetAll(key string, args ...interface{}) []string {
-	t, err := l.TextSnippets.GetAll(key, args...)
-	if err != nil {
-		var locaerr NoTranslationError
-		switch {
-		case errors.As(err, &locaerr):
-			locaerr.Locale = l.GetName()
-			l.errors = append(l.errors, locaerr)
-		default:
-			l.errors = append(l.errors, err)
-		}
-	}
+	t, err := l.snippets(key).all(l.Name, key, args)
+	l.appendError(err)
 	l.appendErrorMissingParam(key, t)
 	return t
 }
 
+// snippets returns the snippets translating the key, falling back to the parents of the locale.
+func (l *Locale) snippets(key string) Snippets {
+	if len(l.TextSnippets[key]) > 0 {
+		return l.TextSnippets
+	}
+	for _, p := range l.parents {
+		if len(p.TextSnippets[key]) > 0 {
+			return p.TextSnippets
+		}
+	}
+	return l.TextSnippets
+}
+
 // GetErrors returns key lookup errors that occurred.
 func (l *Locale) GetErrors() []error {
-	return l.errors
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.errors == nil {
+		return nil
+	}
+	errs := make([]error, len(l.errors))
+	copy(errs, l.errors)
+	return errs
 }
 
 // ResetErrors resets existing errors.
 func (l *Locale) ResetErrors() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.errors = nil
 }
 
+func (l *Locale) appendError(err error) {
+	if err == nil {
+		return
+	}
+
+	var locaErr NoTranslationError
+	var phErr MissingPlaceholderError
+	var msgErr MessageError
+	switch {
+	case errors.As(err, &locaErr):
+		locaErr.Locale = l.GetName()
+		err = locaErr
+	case errors.As(err, &phErr):
+		phErr.Locale = l.GetName()
+		err = phErr
+	case errors.As(err, &msgErr):
+		msgErr.Locale = l.GetName()
+		err = msgErr
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.errors = append(l.errors, err)
+}
+
 func (l *Locale) appendErrorMissingParam(key string, texts []string) {
 	for _, t := range texts {
 		if strings.Contains(t, "%!") &&
 			strings.Contains(t, "(MISSING)") {
-			l.errors = append(l.errors, MissingPlaceholderError{l.GetName(), key, ""})
+			l.appendError(MissingPlaceholderError{l.GetName(), key, ""})
 		}
 	}
 }
@@ -367,36 +497,75 @@ type Snippets map[string][]string
 
 // GetFirst returns the first translation for the snippet.
 func (s Snippets) GetFirst(key string, args ...interface{}) (string, error) {
+	return s.first("", key, args)
+}
+
+// GetAny returns a random translation for the snippet.
+func (s Snippets) GetAny(key string, args ...interface{}) (string, error) {
+	return s.any(nil, "", key, args)
+}
+
+// GetAll returns all translations of the snippet.
+func (s Snippets) GetAll(key string, args ...interface{}) ([]string, error) {
+	return s.all("", key, args)
+}
+
+func (s Snippets) first(locale, key string, args []interface{}) (string, error) {
 	_, ok := s[key]
 	if !ok || len(s[key]) == 0 {
 		return "", NoTranslationError{"", key, ""}
 	}
-	return fmt.Sprintf(s[key][0], args...), nil
+	return format(locale, key, s[key][0], args)
 }
 
-// GetAny returns a random translation for the snippet.
-func (s Snippets) GetAny(key string, args ...interface{}) (string, error) {
+func (s Snippets) any(sel Selector, locale, key string, args []interface{}) (string, error) {
 	_, ok := s[key]
 	if !ok || len(s[key]) == 0 {
 		return "", NoTranslationError{"", key, ""}
 	}
 	if len(s[key]) == 1 {
-		return fmt.Sprintf(s[key][0], args...), nil
+		return format(locale, key, s[key][0], args)
 	}
-	l := len(s[key])
-	r := rand.Intn(l) //nolint:gosec
-	return fmt.Sprintf(s[key][r], args...), nil
+	if sel == nil {
+		sel = defaultSelector
+	}
+	r := sel.Select(key, len(s[key]))
+	return format(locale, key, s[key][r], args)
 }
 
-// GetAll returns all translations of the snippet.
-func (s Snippets) GetAll(key string, args ...interface{}) ([]string, error) {
+func (s Snippets) all(locale, key string, args []interface{}) ([]string, error) {
 	_, ok := s[key]
 	if !ok || len(s[key]) == 0 {
 		return []string{}, NoTranslationError{"", key, ""}
 	}
+	var err error
 	r := make([]string, len(s[key]))
 	for i, v := range s[key] {
-		r[i] = fmt.Sprintf(v, args...)
+		var e error
+		r[i], e = format(locale, key, v, args)
+		if err == nil {
+			err = e
+		}
+	}
+	return r, err
+}
+
+// format formats the translation as message template if args are Args, with fmt.Sprintf otherwise.
+func format(locale, key, text string, args []interface{}) (string, error) {
+	a, ok := isMessageArgs(args)
+	if !ok {
+		return fmt.Sprintf(text, args...), nil
+	}
+
+	t, err := FormatMessage(locale, text, a)
+	var phErr MissingPlaceholderError
+	switch {
+	case err == nil:
+	case errors.As(err, &phErr):
+		phErr.Key = key
+		err = phErr
+	default:
+		err = MessageError{Locale: locale, Key: key, Err: err}
 	}
-	return r, nil
+	return t, err
 }
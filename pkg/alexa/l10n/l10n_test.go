@@ -2,9 +2,10 @@ package l10n_test
 
 import (
 	"bou.ke/monkey"
-	"github.com/drpsychick/go-alexa-lambda/l10n"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
 	"github.com/stretchr/testify/assert"
 	"math/rand"
+	"sync"
 	"testing"
 )
 
@@ -164,13 +165,91 @@ func TestRegistry_Resolve(t *testing.T) {
 	f2, err = r.Resolve("foo")
 	assert.NoError(t, err)
 	assert.Equal(t, f, f2)
+	assert.NotSame(t, f, f2)
 
+	// falls back to the default
 	f2, err = l10n.Resolve("bar")
+	assert.NoError(t, err)
+	assert.Equal(t, f, f2)
+
+	r = l10n.NewRegistry(l10n.WithoutDefaultFallback())
+	err = r.Register(f)
+	assert.NoError(t, err)
+	f2, err = r.Resolve("bar")
 	assert.Error(t, err)
 	assert.Equal(t, "locale 'bar' not found", err.Error())
 	assert.Nil(t, f2)
 }
 
+// Registry fallback chain is covered.
+func TestRegistry_Fallbacks(t *testing.T) {
+	r := l10n.NewRegistry().(*l10n.Registry)
+	assert.NoError(t, r.Register(l10n.NewLocale("en-US")))
+	assert.NoError(t, r.Register(l10n.NewLocale("en-GB")))
+	assert.NoError(t, r.Register(l10n.NewLocale("de-DE")))
+	assert.NoError(t, r.Register(l10n.NewLocale("de-CH")))
+	assert.NoError(t, r.Register(l10n.NewLocale("de-AT"), l10n.AsFallbackFor("de-LI")))
+	assert.NoError(t, r.Register(l10n.NewLocale("zh-Hant-TW")))
+
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{"de-AT", []string{"de-AT", "de-CH", "de-DE", "en-US"}},
+		{"de_at", []string{"de-AT", "de-CH", "de-DE", "en-US"}},
+		{"de-LI", []string{"de-AT", "de-CH", "de-DE", "en-US"}},
+		{"de-BE", []string{"de-AT", "de-CH", "de-DE", "en-US"}},
+		{"en-AU", []string{"en-GB", "en-US"}},
+		{"zh-hant-tw", []string{"zh-Hant-TW", "en-US"}},
+		{"zh-Hans-CN", []string{"zh-Hant-TW", "en-US"}},
+		{"fr-FR", []string{"en-US"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.Fallbacks(tt.locale))
+		})
+	}
+}
+
+// Locale key-level fallback is covered.
+func TestRegistry_ResolveKeyFallback(t *testing.T) {
+	r := l10n.NewRegistry()
+	en := l10n.NewLocale("en-US")
+	en.Set("both", []string{"en both"})
+	en.Set("only_en", []string{"only en"})
+	de := l10n.NewLocale("de-DE")
+	de.Set("both", []string{"de both"})
+	de.Set("only_de", []string{"nur de"})
+	at := l10n.NewLocale("de-AT")
+	at.Set("at", []string{"servus"})
+	assert.NoError(t, r.Register(en))
+	assert.NoError(t, r.Register(de))
+	assert.NoError(t, r.Register(at))
+
+	l, err := r.Resolve("de-AT")
+	assert.NoError(t, err)
+	assert.Equal(t, "de-AT", l.GetName())
+	assert.Equal(t, "servus", l.Get("at"))
+	assert.Equal(t, "de both", l.GetAny("both"))
+	assert.Equal(t, []string{"nur de"}, l.GetAll("only_de"))
+	assert.Empty(t, l.GetErrors())
+
+	// no fallback to another language
+	assert.Empty(t, l.Get("only_en"))
+	assert.Len(t, l.GetErrors(), 1)
+	assert.Equal(t, "locale de-AT: no translation for key 'only_en'", l.GetErrors()[0].Error())
+
+	// unknown locale of the language resolves to the first match
+	l, err = r.Resolve("de-CH")
+	assert.NoError(t, err)
+	assert.Equal(t, "de-AT", l.GetName())
+
+	// SetDefault requires a registered locale
+	assert.Error(t, r.SetDefault("de-CH"))
+	assert.NoError(t, r.SetDefault("de_de"))
+	assert.Equal(t, "de-DE", r.GetDefault().GetName())
+}
+
 // Registry no default is covered.
 func TestRegistry_ErrorsIfNoDefault(t *testing.T) {
 	r := l10n.NewRegistry()
@@ -264,3 +343,46 @@ func TestLocale_ErrorNoParam(t *testing.T) {
 	assert.NotEmpty(t, l.GetErrors())
 	assert.Equal(t, "locale de-DE: key '"+WithParam+"' is missing a placeholder in translation", l.GetErrors()[0].Error())
 }
+
+// Locale instances of requests do not share errors.
+func TestRegistry_ResolveScopesErrors(t *testing.T) {
+	r := l10n.NewRegistry()
+	err := r.Register(l10n.NewLocale("de-DE"))
+	assert.NoError(t, err)
+
+	l1, err := r.Resolve("de-DE")
+	assert.NoError(t, err)
+	l2, err := r.Resolve("de-DE")
+	assert.NoError(t, err)
+
+	// snippets are shared
+	l1.Set("foo", []string{"bar"})
+	assert.Equal(t, "bar", l2.Get("foo"))
+	assert.Equal(t, "bar", r.GetDefault().Get("foo"))
+
+	// errors are not
+	assert.Empty(t, l1.Get("not exists"))
+	assert.Len(t, l1.GetErrors(), 1)
+	assert.Empty(t, l2.GetErrors())
+	assert.Empty(t, r.GetLocales()["de-DE"].GetErrors())
+}
+
+// Locale concurrent use is covered.
+func TestLocale_Concurrent(t *testing.T) {
+	l := l10n.NewLocale("de-DE")
+	l.Set("foo", []string{"bar", "baz"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.GetAny("foo")
+			l.Get("not exists")
+			l.GetErrors()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, l.GetErrors(), 10)
+}
@@ -0,0 +1,187 @@
+package l10n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/ssml"
+)
+
+// Lint issue types.
+const (
+	LintMissingKey   = "missing-key"
+	LintPlaceholders = "placeholders"
+	LintInvalidSSML  = "invalid-ssml"
+	LintInvalidMsg   = "invalid-message"
+)
+
+// LintIssue is a problem of a translation found by Lint.
+type LintIssue struct {
+	Type   string
+	Locale string
+	Key    string
+	// Index is the variant of the key, -1 if the issue concerns the whole key.
+	Index   int
+	Message string
+}
+
+// Error returns a string of the issue.
+func (i LintIssue) Error() string {
+	if i.Index < 0 {
+		return fmt.Sprintf("locale %s: key '%s': %s", i.Locale, i.Key, i.Message)
+	}
+	return fmt.Sprintf("locale %s: key '%s[%d]': %s", i.Locale, i.Key, i.Index, i.Message)
+}
+
+// Lint checks all registered locales against the default locale.
+//
+// It reports keys missing in a locale, placeholder counts or message arguments that differ between
+// the variants of a key or from the default locale, invalid message templates and invalid SSML
+// in keys ending with "_SSML".
+func Lint(r LocaleRegistry) []LintIssue {
+	var def *Locale
+	var locales []*Locale
+	for _, li := range r.GetLocales() {
+		l, ok := li.(*Locale)
+		if !ok {
+			continue
+		}
+		locales = append(locales, l)
+	}
+	sort.Slice(locales, func(i, j int) bool {
+		return locales[i].Name < locales[j].Name
+	})
+	if d := r.GetDefault(); d != nil {
+		for _, l := range locales {
+			if l.Name == d.GetName() {
+				def = l
+			}
+		}
+	}
+
+	var issues []LintIssue
+	for _, l := range locales {
+		issues = append(issues, lintLocale(l, def)...)
+	}
+	return issues
+}
+
+// lintLocale checks a single locale, against the default locale if def is not nil.
+func lintLocale(l, def *Locale) []LintIssue { //nolint:cyclop
+	var issues []LintIssue
+
+	if def != nil && def != l {
+		for _, k := range sortedKeys(def.TextSnippets) {
+			if len(l.TextSnippets[k]) == 0 {
+				issues = append(issues, LintIssue{
+					Type: LintMissingKey, Locale: l.Name, Key: k, Index: -1,
+					Message: fmt.Sprintf("missing, translated in %s", def.Name),
+				})
+			}
+		}
+	}
+
+	for _, k := range sortedKeys(l.TextSnippets) {
+		want, known := -1, map[string]bool(nil)
+		if def != nil && def != l && len(def.TextSnippets[k]) > 0 {
+			want = countPlaceholders(def.TextSnippets[k][0])
+			known = lintMessageArgs(def.TextSnippets[k])
+		}
+
+		for i, v := range l.TextSnippets[k] {
+			n := countPlaceholders(v)
+			switch {
+			case want < 0:
+				// the first variant defines the placeholders
+				want = n
+			case n != want:
+				issues = append(issues, LintIssue{
+					Type: LintPlaceholders, Locale: l.Name, Key: k, Index: i,
+					Message: fmt.Sprintf("has %d placeholders, expected %d", n, want),
+				})
+			}
+
+			if isMessage(k, v) {
+				issues = append(issues, lintMessage(l.Name, k, i, v, known)...)
+			}
+
+			if strings.HasSuffix(k, KeyPostfixSSML) {
+				if err := ssml.Check(v); err != nil {
+					issues = append(issues, LintIssue{
+						Type: LintInvalidSSML, Locale: l.Name, Key: k, Index: i,
+						Message: err.Error(),
+					})
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// countPlaceholders returns the number of fmt verbs in the text, "%%" is not counted.
+func countPlaceholders(s string) int {
+	var n int
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '%' {
+			i++
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// isMessage returns true if the text looks like a message template, samples use braces for slots.
+func isMessage(key, s string) bool {
+	return !strings.HasSuffix(key, KeyPostfixSamples) && strings.ContainsAny(s, "{}")
+}
+
+// lintMessage checks the message template and its arguments against the known arguments, if not nil.
+func lintMessage(locale, key string, idx int, s string, known map[string]bool) []LintIssue {
+	args, err := messageArgs(s)
+	if err != nil {
+		return []LintIssue{{
+			Type: LintInvalidMsg, Locale: locale, Key: key, Index: idx,
+			Message: err.Error(),
+		}}
+	}
+	if known == nil {
+		return nil
+	}
+
+	var issues []LintIssue
+	for _, a := range args {
+		if !known[a] {
+			issues = append(issues, LintIssue{
+				Type: LintPlaceholders, Locale: locale, Key: key, Index: idx,
+				Message: fmt.Sprintf("has unknown message argument '%s'", a),
+			})
+		}
+	}
+	return issues
+}
+
+// lintMessageArgs returns the arguments of all message templates of the key.
+func lintMessageArgs(values []string) map[string]bool {
+	known := map[string]bool{}
+	for _, v := range values {
+		args, _ := messageArgs(v)
+		for _, a := range args {
+			known[a] = true
+		}
+	}
+	return known
+}
+
+func sortedKeys(s Snippets) []string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,82 @@
+package l10n_test
+
+import (
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	en := l10n.NewLocale("en-US")
+	en.Set("Greeting", []string{"Hello %s", "Hi %s"})
+	en.Set("Status_SSML", []string{"<speak>All %d%% fine</speak>"})
+	en.Set("Only_EN", []string{"only english"})
+	de := l10n.NewLocale("de-DE")
+	de.Set("Greeting", []string{"Hallo %s", "Servus"})
+	de.Set("Status_SSML", []string{"<speak>Alles %d%% gut</speak>", "<speak>Alles gut", "Alles gut"})
+
+	r := l10n.NewRegistry()
+	assert.NoError(t, r.Register(en, l10n.AsDefault()))
+	assert.NoError(t, r.Register(de))
+
+	issues := l10n.Lint(r)
+
+	var got []string
+	for _, i := range issues {
+		assert.Equal(t, "de-DE", i.Locale)
+		got = append(got, i.Type+" "+i.Key)
+	}
+	assert.Equal(t, []string{
+		l10n.LintMissingKey + " Only_EN",
+		l10n.LintPlaceholders + " Greeting",
+		l10n.LintPlaceholders + " Status_SSML",
+		l10n.LintInvalidSSML + " Status_SSML",
+		l10n.LintPlaceholders + " Status_SSML",
+		l10n.LintInvalidSSML + " Status_SSML",
+	}, got)
+	assert.Equal(t, "locale de-DE: key 'Only_EN': missing, translated in en-US", issues[0].Error())
+	assert.Equal(t, "locale de-DE: key 'Greeting[1]': has 0 placeholders, expected 1", issues[1].Error())
+}
+
+func TestLint_Valid(t *testing.T) {
+	en := l10n.NewLocale("en-US")
+	en.Set("Launch_SSML", []string{"<speak>Hello <break time=\"1s\"/> there</speak>"})
+	r := l10n.NewRegistry()
+	assert.NoError(t, r.Register(en, l10n.AsDefault()))
+
+	assert.Empty(t, l10n.Lint(r))
+}
+
+func TestLint_SSMLWhitelist(t *testing.T) {
+	en := l10n.NewLocale("en-US")
+	en.Set("Launch_SSML", []string{"<speak><foo>Hello</foo></speak>"})
+	r := l10n.NewRegistry()
+	assert.NoError(t, r.Register(en, l10n.AsDefault()))
+
+	issues := l10n.Lint(r)
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, l10n.LintInvalidSSML, issues[0].Type)
+	}
+}
+
+func TestLint_Messages(t *testing.T) {
+	en := l10n.NewLocale("en-US")
+	en.Set("Days", []string{"{n, plural, one {# day} other {# days}}"})
+	en.Set("Intent_Samples", []string{"in {Region}", "in {Area} {Region}"})
+	de := l10n.NewLocale("de-DE")
+	de.Set("Days", []string{"{n, plural, one {# Tag} other {# Tage}} {name}", "{n, plural, one {# Tag}}"})
+	de.Set("Intent_Samples", []string{"in {Gebiet}"})
+
+	r := l10n.NewRegistry()
+	assert.NoError(t, r.Register(en, l10n.AsDefault()))
+	assert.NoError(t, r.Register(de))
+
+	issues := l10n.Lint(r)
+
+	assert.Len(t, issues, 2)
+	assert.Equal(t, l10n.LintPlaceholders, issues[0].Type)
+	assert.Equal(t, "locale de-DE: key 'Days[0]': has unknown message argument 'name'", issues[0].Error())
+	assert.Equal(t, l10n.LintInvalidMsg, issues[1].Type)
+	assert.Equal(t, 1, issues[1].Index)
+}
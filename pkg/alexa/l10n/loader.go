@@ -0,0 +1,214 @@
+package l10n
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/yaml.v3"
+)
+
+// Errors of locale files.
+var (
+	ErrUnsupportedFormat = errors.New("unsupported file format")
+	ErrInvalidLocaleName = errors.New("file name is not a locale like 'en-US'")
+	ErrDuplicateLocale   = errors.New("locale is defined in more than one file")
+	ErrInvalidValue      = errors.New("value must be a string or a list of strings")
+	ErrEmptyValue        = errors.New("value must not be an empty list")
+)
+
+// decoders are the supported locale file formats by extension.
+var decoders = map[string]func(data []byte, v interface{}) error{
+	".json": jsoniter.Unmarshal,
+	".yaml": yaml.Unmarshal,
+	".yml":  yaml.Unmarshal,
+	".toml": toml.Unmarshal,
+}
+
+// encoders are the supported locale file formats by extension.
+var encoders = map[string]func(w io.Writer, v interface{}) error{
+	".json": func(w io.Writer, v interface{}) error {
+		enc := jsoniter.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	},
+	".yaml": encodeYAML,
+	".yml":  encodeYAML,
+	".toml": func(w io.Writer, v interface{}) error {
+		return toml.NewEncoder(w).Encode(v)
+	},
+}
+
+func encodeYAML(w io.Writer, v interface{}) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// FileError is an error in a locale file, with the key if it concerns a single key.
+type FileError struct {
+	File string
+	Key  string
+	Err  error
+}
+
+// Error returns a string of the error.
+func (e FileError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%s: %v", e.File, e.Err)
+	}
+	return fmt.Sprintf("%s: key '%s': %v", e.File, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
+// LoadFile returns the locale defined in the file, the locale name is the file name, e.g. "de-DE.yaml".
+func LoadFile(file string) (*Locale, error) {
+	data, err := ioutil.ReadFile(file) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	return parseLocale(file, filepath.Base(file), data)
+}
+
+// WriteFile writes the locale to a JSON, YAML or TOML file, depending on the extension.
+func WriteFile(file string, l *Locale) error {
+	encode, ok := encoders[strings.ToLower(filepath.Ext(file))]
+	if !ok {
+		return FileError{File: file, Err: ErrUnsupportedFormat}
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, l.TextSnippets); err != nil {
+		return FileError{File: file, Err: err}
+	}
+	return ioutil.WriteFile(file, buf.Bytes(), 0o644) //nolint:gosec
+}
+
+// LoadFS returns the locales of all JSON, YAML and TOML files in the directory of fsys, sorted by name.
+//
+// Other files are ignored, so the directory can be an embed.FS with a README.
+func LoadFS(fsys fs.FS, dir string) ([]*Locale, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{}
+	var locales []*Locale
+	for _, e := range entries {
+		if e.IsDir() || decoders[strings.ToLower(path.Ext(e.Name()))] == nil {
+			continue
+		}
+
+		file := path.Join(dir, e.Name())
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		l, err := parseLocale(file, e.Name(), data)
+		if err != nil {
+			return nil, err
+		}
+		if f, ok := files[l.Name]; ok {
+			return nil, FileError{File: file, Err: fmt.Errorf("%w: %s", ErrDuplicateLocale, f)}
+		}
+		files[l.Name] = file
+		locales = append(locales, l)
+	}
+
+	sort.Slice(locales, func(i, j int) bool {
+		return locales[i].Name < locales[j].Name
+	})
+	return locales, nil
+}
+
+// RegisterFS loads the locales of the directory in fsys and registers them with the registry.
+//
+// The first registered locale becomes the default, unless the registry already has one.
+func RegisterFS(r LocaleRegistry, fsys fs.FS, dir string) error {
+	locales, err := LoadFS(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range locales {
+		if err := r.Register(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseLocale returns the locale of the file content, named after the base name of the file.
+func parseLocale(file, base string, data []byte) (*Locale, error) {
+	ext := path.Ext(base)
+	decode, ok := decoders[strings.ToLower(ext)]
+	if !ok {
+		return nil, FileError{File: file, Err: ErrUnsupportedFormat}
+	}
+
+	name := strings.TrimSuffix(base, ext)
+	if !validTag(name) || canonicalTag(name) != name {
+		return nil, FileError{File: file, Err: ErrInvalidLocaleName}
+	}
+
+	var raw map[string]interface{}
+	if err := decode(data, &raw); err != nil {
+		return nil, FileError{File: file, Err: err}
+	}
+
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	l := NewLocale(name)
+	for _, k := range keys {
+		values, err := snippetValues(raw[k])
+		if err != nil {
+			return nil, FileError{File: file, Key: k, Err: err}
+		}
+		l.Set(k, values)
+	}
+	return l, nil
+}
+
+// snippetValues returns the translations of a decoded value.
+func snippetValues(v interface{}) ([]string, error) {
+	switch val := v.(type) {
+	case string:
+		return []string{val}, nil
+	case []interface{}:
+		if len(val) == 0 {
+			return nil, ErrEmptyValue
+		}
+		values := make([]string, 0, len(val))
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, ErrInvalidValue
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	default:
+		return nil, ErrInvalidValue
+	}
+}
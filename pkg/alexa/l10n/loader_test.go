@@ -0,0 +1,107 @@
+package l10n_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en-US.json": {Data: []byte(`{"Greeting": ["Hi", "Hello"], "Bye": "Bye"}`)},
+		"locales/de-DE.yaml": {Data: []byte("Greeting:\n  - Hallo\nBye: Tschüss\n")},
+		"locales/fr-FR.toml": {Data: []byte("Greeting = [\"Salut\"]\n\"AMAZON.StopIntent_Samples\" = \"stop\"\n")},
+		"locales/README.md":  {Data: []byte("# locales")},
+	}
+
+	ls, err := l10n.LoadFS(fsys, "locales")
+	assert.NoError(t, err)
+	assert.Len(t, ls, 3)
+	assert.Equal(t, "de-DE", ls[0].Name)
+	assert.Equal(t, []string{"Hallo"}, ls[0].GetAll("Greeting"))
+	assert.Equal(t, "Tschüss", ls[0].Get("Bye"))
+	assert.Equal(t, []string{"Hi", "Hello"}, ls[1].GetAll("Greeting"))
+	assert.Equal(t, "stop", ls[2].Get("AMAZON.StopIntent_Samples"))
+
+	r := l10n.NewRegistry()
+	err = l10n.RegisterFS(r, fsys, "locales")
+	assert.NoError(t, err)
+	assert.Len(t, r.GetLocales(), 3)
+	assert.Equal(t, "de-DE", r.GetDefault().GetName())
+}
+
+func TestLoadFS_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		err  error
+		msg  string
+	}{
+		{
+			name: "invalid value",
+			fsys: fstest.MapFS{"l/en-US.yaml": {Data: []byte("Greeting:\n  - Hi\n  - 42\n")}},
+			err:  l10n.ErrInvalidValue,
+			msg:  "l/en-US.yaml: key 'Greeting': value must be a string or a list of strings",
+		},
+		{
+			name: "nested",
+			fsys: fstest.MapFS{"l/en-US.toml": {Data: []byte("AMAZON.StopIntent_Samples = \"stop\"\n")}},
+			err:  l10n.ErrInvalidValue,
+			msg:  "l/en-US.toml: key 'AMAZON': value must be a string or a list of strings",
+		},
+		{
+			name: "empty list",
+			fsys: fstest.MapFS{"l/en-US.json": {Data: []byte(`{"Greeting": []}`)}},
+			err:  l10n.ErrEmptyValue,
+			msg:  "l/en-US.json: key 'Greeting': value must not be an empty list",
+		},
+		{
+			name: "invalid name",
+			fsys: fstest.MapFS{"l/english.json": {Data: []byte(`{}`)}},
+			err:  l10n.ErrInvalidLocaleName,
+			msg:  "l/english.json: file name is not a locale like 'en-US'",
+		},
+		{
+			name: "duplicate",
+			fsys: fstest.MapFS{
+				"l/en-US.json": {Data: []byte(`{}`)},
+				"l/en-US.yaml": {Data: []byte(``)},
+			},
+			err: l10n.ErrDuplicateLocale,
+			msg: "l/en-US.yaml: locale is defined in more than one file: l/en-US.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := l10n.LoadFS(tt.fsys, "l")
+			assert.True(t, errors.Is(err, tt.err))
+			var fileErr l10n.FileError
+			assert.True(t, errors.As(err, &fileErr))
+			assert.Equal(t, tt.msg, err.Error())
+		})
+	}
+
+	// syntax error
+	_, err := l10n.LoadFS(fstest.MapFS{"l/en-US.json": {Data: []byte(`{`)}}, "l")
+	var fileErr l10n.FileError
+	assert.True(t, errors.As(err, &fileErr))
+	assert.Equal(t, "l/en-US.json", fileErr.File)
+}
+
+func TestLoadFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "en-GB.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte("Greeting: Hello\n"), 0o600))
+
+	l, err := l10n.LoadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "en-GB", l.GetName())
+	assert.Equal(t, "Hello", l.Get("Greeting"))
+
+	_, err = l10n.LoadFile(filepath.Join(t.TempDir(), "en-GB.ini"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,461 @@
+package l10n
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidMessage is returned for message templates that cannot be parsed or formatted.
+var ErrInvalidMessage = errors.New("invalid message")
+
+// Args are the named arguments of a message template.
+//
+// Passing Args as the only argument to Get, GetAny or GetAll formats the translation as
+// ICU MessageFormat template instead of using fmt.Sprintf:
+//
+//	You have {count, plural, =0 {no messages} one {# message} other {# messages}}.
+//	{gender, select, female {She} male {He} other {They}} said hello to {name}.
+type Args map[string]interface{}
+
+// MessageError defines an error of a message template.
+type MessageError struct {
+	Locale string
+	Key    string
+	Err    error
+}
+
+// Error returns a string of the error.
+func (e MessageError) Error() string {
+	return fmt.Sprintf("locale %s: key '%s': %v", e.Locale, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e MessageError) Unwrap() error {
+	return e.Err
+}
+
+// FormatMessage formats the message template with the plural rules of the locale.
+//
+// Arguments missing in args are kept as "{name}" and returned as MissingPlaceholderError.
+func FormatMessage(locale, msg string, args Args) (string, error) {
+	m, err := parseMessage(msg)
+	if err != nil {
+		return msg, err
+	}
+
+	f := &formatter{locale: locale, args: args}
+	var b strings.Builder
+	if err := f.format(&b, m, 0, false); err != nil {
+		return msg, err
+	}
+	if f.missing != "" {
+		return b.String(), MissingPlaceholderError{Locale: locale, Placeholder: f.missing}
+	}
+	return b.String(), nil
+}
+
+// messageArgs returns the sorted names of all arguments of the message template.
+func messageArgs(msg string) ([]string, error) {
+	m, err := parseMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	var walk func(m message)
+	walk = func(m message) {
+		for _, p := range m {
+			if p.name != "" {
+				names[p.name] = true
+			}
+			for _, c := range p.cases {
+				walk(c.msg)
+			}
+		}
+	}
+	walk(m)
+
+	args := make([]string, 0, len(names))
+	for n := range names {
+		args = append(args, n)
+	}
+	sort.Strings(args)
+	return args, nil
+}
+
+// Message part kinds.
+const (
+	partText = iota
+	partArg
+	partNumber
+	partPlural
+	partSelect
+	partHash
+)
+
+type message []part
+
+type part struct {
+	kind   int
+	text   string
+	name   string
+	offset float64
+	cases  []messageCase
+}
+
+type messageCase struct {
+	selector string
+	msg      message
+}
+
+// find returns the message of the case matching the selector.
+func (p part) find(selector string) (message, bool) {
+	for _, c := range p.cases {
+		if c.selector == selector {
+			return c.msg, true
+		}
+	}
+	return nil, false
+}
+
+type parser struct {
+	s   []rune
+	pos int
+}
+
+func parseMessage(msg string) (message, error) {
+	p := &parser{s: []rune(msg)}
+	m, err := p.message(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected '%c'", p.s[p.pos])
+	}
+	return m, nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at position %d", ErrInvalidMessage, fmt.Sprintf(format, args...), p.pos)
+}
+
+// message parses text and arguments until an unmatched '}' or the end.
+func (p *parser) message(inPlural bool) (message, error) { //nolint:cyclop
+	var m message
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			m = append(m, part{kind: partText, text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '\'':
+			p.quoted(&text, inPlural)
+		case c == '{':
+			flush()
+			a, err := p.argument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, a)
+		case c == '}':
+			flush()
+			return m, nil
+		case c == '#' && inPlural:
+			flush()
+			m = append(m, part{kind: partHash})
+			p.pos++
+		default:
+			text.WriteRune(c)
+			p.pos++
+		}
+	}
+	flush()
+	return m, nil
+}
+
+// quoted parses an apostrophe: two apostrophes are a literal one and an apostrophe before a
+// syntax character starts a quoted literal, any other apostrophe is kept as is.
+func (p *parser) quoted(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.pos >= len(p.s) {
+		text.WriteRune('\'')
+		return
+	}
+
+	switch c := p.s[p.pos]; {
+	case c == '\'':
+		text.WriteRune('\'')
+		p.pos++
+		return
+	case c == '{' || c == '}' || c == '#' && inPlural:
+	default:
+		text.WriteRune('\'')
+		return
+	}
+
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		if c != '\'' {
+			text.WriteRune(c)
+			continue
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == '\'' {
+			text.WriteRune('\'')
+			p.pos++
+			continue
+		}
+		return
+	}
+}
+
+// argument parses "{name}", "{name, number}", "{name, plural, ...}" or "{name, select, ...}".
+//
+// A '#' in a select within a plural refers to the number of the plural.
+func (p *parser) argument(inPlural bool) (part, error) {
+	p.pos++ // {
+	name := p.identifier()
+	if name == "" {
+		return part{}, p.errorf("missing argument name")
+	}
+
+	if p.consume('}') {
+		return part{kind: partArg, name: name}, nil
+	}
+	if !p.consume(',') {
+		return part{}, p.errorf("expected ',' or '}' after argument '%s'", name)
+	}
+
+	typ := p.identifier()
+	switch typ {
+	case "number":
+		if !p.consume('}') {
+			return part{}, p.errorf("unsupported number style of argument '%s'", name)
+		}
+		return part{kind: partNumber, name: name}, nil
+	case "plural", "select":
+	default:
+		return part{}, p.errorf("unsupported argument type '%s'", typ)
+	}
+	if !p.consume(',') {
+		return part{}, p.errorf("expected ',' after %s of argument '%s'", typ, name)
+	}
+
+	a := part{kind: partSelect, name: name}
+	if typ == "plural" {
+		a.kind = partPlural
+		if err := p.offset(&a); err != nil {
+			return part{}, err
+		}
+	}
+	if err := p.cases(&a, inPlural || a.kind == partPlural); err != nil {
+		return part{}, err
+	}
+	return a, nil
+}
+
+// offset parses the optional "offset:n" of a plural argument.
+func (p *parser) offset(a *part) error {
+	p.skipSpace()
+	const prefix = "offset:"
+	if !strings.HasPrefix(string(p.s[p.pos:]), prefix) {
+		return nil
+	}
+	p.pos += len(prefix)
+	n, err := strconv.ParseFloat(p.identifier(), 64)
+	if err != nil {
+		return p.errorf("invalid offset of argument '%s'", a.name)
+	}
+	a.offset = n
+	return nil
+}
+
+// cases parses the "selector {message}" pairs up to the closing '}' of the argument.
+func (p *parser) cases(a *part, inPlural bool) error {
+	for {
+		if p.consume('}') {
+			break
+		}
+		sel := p.identifier()
+		if sel == "" {
+			return p.errorf("expected selector or '}' in argument '%s'", a.name)
+		}
+		if a.kind == partSelect && strings.HasPrefix(sel, "=") {
+			return p.errorf("invalid selector '%s' in argument '%s'", sel, a.name)
+		}
+		if !p.consume('{') {
+			return p.errorf("expected '{' after selector '%s'", sel)
+		}
+		m, err := p.message(inPlural)
+		if err != nil {
+			return err
+		}
+		if !p.consume('}') {
+			return p.errorf("unclosed selector '%s' in argument '%s'", sel, a.name)
+		}
+		a.cases = append(a.cases, messageCase{selector: sel, msg: m})
+	}
+
+	if _, ok := a.find("other"); !ok {
+		return p.errorf("argument '%s' has no 'other' case", a.name)
+	}
+	return nil
+}
+
+// identifier skips white space and returns the following name, number or selector.
+func (p *parser) identifier() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if unicode.IsSpace(c) || strings.ContainsRune("{},'#", c) {
+			break
+		}
+		p.pos++
+	}
+	return string(p.s[start:p.pos])
+}
+
+// consume skips white space and the rune, if it is next.
+func (p *parser) consume(r rune) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == r {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+type formatter struct {
+	locale  string
+	args    Args
+	missing string
+}
+
+// format writes the message, number is the value of '#' within a plural case.
+//
+// Numbers are formatted with the separators of the locale, see FormatNumber.
+func (f *formatter) format(b *strings.Builder, m message, number float64, inPlural bool) error { //nolint:cyclop
+	for _, p := range m {
+		if p.kind == partText {
+			b.WriteString(p.text)
+			continue
+		}
+		if p.kind == partHash {
+			if inPlural {
+				b.WriteString(FormatNumber(f.locale, number))
+			}
+			continue
+		}
+
+		v, ok := f.args[p.name]
+		if !ok {
+			if f.missing == "" {
+				f.missing = p.name
+			}
+			b.WriteString("{" + p.name + "}")
+			continue
+		}
+
+		switch p.kind {
+		case partArg:
+			b.WriteString(fmt.Sprint(v))
+		case partNumber:
+			n, err := toNumber(v)
+			if err != nil {
+				return fmt.Errorf("%w: argument '%s': %v", ErrInvalidMessage, p.name, err)
+			}
+			b.WriteString(FormatNumber(f.locale, n))
+		case partSelect:
+			c, ok := p.find(fmt.Sprint(v))
+			if !ok {
+				c, _ = p.find("other")
+			}
+			if err := f.format(b, c, number, inPlural); err != nil {
+				return err
+			}
+		case partPlural:
+			n, err := toNumber(v)
+			if err != nil {
+				return fmt.Errorf("%w: argument '%s': %v", ErrInvalidMessage, p.name, err)
+			}
+			c, ok := p.find("=" + formatNumber(n))
+			if !ok {
+				c, ok = p.find(PluralCategory(f.locale, n-p.offset))
+			}
+			if !ok {
+				c, _ = p.find("other")
+			}
+			if err := f.format(b, c, n-p.offset, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// toNumber returns the value of numeric types and strings.
+func toNumber(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int8:
+		return float64(n), nil
+	case int16:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint:
+		return float64(n), nil
+	case uint8:
+		return float64(n), nil
+	case uint16:
+		return float64(n), nil
+	case uint32:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case float32:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	case string:
+		return strconv.ParseFloat(n, 64)
+	}
+	return 0, fmt.Errorf("%T is not a number", v)
+}
+
+// formatNumber returns the number as used in exact plural selectors like "=1".
+func formatNumber(n float64) string {
+	if n == math.Trunc(n) && math.Abs(n) < 1e15 {
+		return strconv.FormatInt(int64(n), 10)
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// isMessageArgs returns the named arguments if they are the only argument.
+func isMessageArgs(args []interface{}) (Args, bool) {
+	if len(args) != 1 {
+		return nil, false
+	}
+	a, ok := args[0].(Args)
+	return a, ok
+}
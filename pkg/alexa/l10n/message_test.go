@@ -0,0 +1,141 @@
+package l10n_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMessage(t *testing.T) {
+	plural := "{count, plural, =0 {no messages} one {# message} other {# messages}}"
+	tests := []struct {
+		name   string
+		locale string
+		msg    string
+		args   l10n.Args
+		want   string
+	}{
+		{"text", "en-US", "Hello world", nil, "Hello world"},
+		{"argument", "en-US", "Hello {name}!", l10n.Args{"name": "Alexa"}, "Hello Alexa!"},
+		{"number", "en-US", "{n, number} left", l10n.Args{"n": 2.5}, "2.5 left"},
+		{"number german", "de-DE", "{n, number} Tage", l10n.Args{"n": 1234.5}, "1.234,5 Tage"},
+		{"plural exact", "en-US", plural, l10n.Args{"count": 0}, "no messages"},
+		{"plural one", "en-US", plural, l10n.Args{"count": 1}, "1 message"},
+		{"plural other", "en-US", plural, l10n.Args{"count": 3}, "3 messages"},
+		{"plural decimal", "en-US", plural, l10n.Args{"count": 1.5}, "1.5 messages"},
+		{"plural french zero", "fr-FR", "{n, plural, one {# jour} other {# jours}}", l10n.Args{"n": 0}, "0 jour"},
+		{"plural german zero", "de-DE", "{n, plural, one {# Tag} other {# Tage}}", l10n.Args{"n": 0}, "0 Tage"},
+		{
+			"plural offset", "en-US",
+			"{n, plural, offset:1 =0 {nobody} =1 {{name}} one {{name} and # other} other {{name} and # others}}",
+			l10n.Args{"n": 3, "name": "Bob"}, "Bob and 2 others",
+		},
+		{
+			"select", "en-US",
+			"{gender, select, female {She} male {He} other {They}} said hi",
+			l10n.Args{"gender": "female"}, "She said hi",
+		},
+		{
+			"select other", "en-US",
+			"{gender, select, female {She} male {He} other {They}} said hi",
+			l10n.Args{"gender": "robot"}, "They said hi",
+		},
+		{
+			"select in plural", "en-US",
+			"{n, plural, one {{unit, select, m {# meter} other {# unit}}} other {{unit, select, m {# meters} other {# units}}}}",
+			l10n.Args{"n": 5, "unit": "m"}, "5 meters",
+		},
+		{"apostrophe", "en-US", "Don't use '{braces}' or ''", nil, "Don't use {braces} or '"},
+		{"ssml", "en-US", `<speak>{n, plural, one {one <break time="1s"/> item} other {# items}}</speak>`,
+			l10n.Args{"n": 1}, `<speak>one <break time="1s"/> item</speak>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l10n.FormatMessage(tt.locale, tt.msg, tt.args)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatMessage_Errors(t *testing.T) {
+	got, err := l10n.FormatMessage("en-US", "Hello {name}", l10n.Args{})
+	assert.Equal(t, "Hello {name}", got)
+	var phErr l10n.MissingPlaceholderError
+	assert.True(t, errors.As(err, &phErr))
+	assert.Equal(t, "name", phErr.Placeholder)
+
+	for _, msg := range []string{
+		"Hello {name",
+		"Hello name}",
+		"{n, plural, one {#}}",
+		"{n, select, =1 {one} other {#}}",
+		"{n, date}",
+		"{}",
+	} {
+		_, err := l10n.FormatMessage("en-US", msg, l10n.Args{"n": 1})
+		assert.True(t, errors.Is(err, l10n.ErrInvalidMessage), msg)
+	}
+
+	_, err = l10n.FormatMessage("en-US", "{n, plural, other {#}}", l10n.Args{"n": "many"})
+	assert.True(t, errors.Is(err, l10n.ErrInvalidMessage))
+}
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      float64
+		want   string
+	}{
+		{"en-US", 1, l10n.PluralOne},
+		{"en-US", 0, l10n.PluralOther},
+		{"en-US", 1.5, l10n.PluralOther},
+		{"de-DE", 1, l10n.PluralOne},
+		{"fr-FR", 0, l10n.PluralOne},
+		{"fr-FR", 1.5, l10n.PluralOne},
+		{"fr-FR", 2, l10n.PluralOther},
+		{"fr-FR", 1000000, l10n.PluralMany},
+		{"es-ES", 1, l10n.PluralOne},
+		{"it-IT", 2, l10n.PluralOther},
+		{"pt-BR", 0, l10n.PluralOne},
+		{"ja-JP", 1, l10n.PluralOther},
+		{"hi-IN", 0, l10n.PluralOne},
+		{"ru-RU", 21, l10n.PluralOne},
+		{"ru-RU", 3, l10n.PluralFew},
+		{"ru-RU", 11, l10n.PluralMany},
+		{"pl-PL", 22, l10n.PluralFew},
+		{"pl-PL", 21, l10n.PluralMany},
+		{"ar-SA", 0, l10n.PluralZero},
+		{"ar-SA", 2, l10n.PluralTwo},
+		{"ar-SA", 105, l10n.PluralFew},
+		{"ar-SA", 111, l10n.PluralMany},
+		{"xx", 1, l10n.PluralOne},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, l10n.PluralCategory(tt.locale, tt.n), "%s %v", tt.locale, tt.n)
+	}
+}
+
+func TestLocale_GetArgs(t *testing.T) {
+	l := l10n.NewLocale("de-DE")
+	l.Set("Days", []string{"{n, plural, one {ein Tag} other {# Tage}}", "{n, plural, one {# Tag} other {# Tage}}"})
+	l.Set("Printf", []string{"%d Tage"})
+
+	assert.Equal(t, "ein Tag", l.Get("Days", l10n.Args{"n": 1}))
+	assert.Equal(t, []string{"2 Tage", "2 Tage"}, l.GetAll("Days", l10n.Args{"n": 2}))
+	assert.Contains(t, []string{"ein Tag", "1 Tag"}, l.GetAny("Days", l10n.Args{"n": 1}))
+	assert.Equal(t, "2 Tage", l.Get("Printf", 2))
+	assert.Empty(t, l.GetErrors())
+
+	assert.Empty(t, l.Get("Unknown", l10n.Args{}))
+	l.Set("Missing", []string{"{n} Tage"})
+	assert.Equal(t, "{n} Tage", l.Get("Missing", l10n.Args{}))
+	errs := l.GetErrors()
+	assert.Len(t, errs, 2)
+	assert.Equal(t, l10n.MissingPlaceholderError{Locale: "de-DE", Key: "Missing", Placeholder: "n"}, errs[1])
+}
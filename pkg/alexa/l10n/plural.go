@@ -0,0 +1,183 @@
+package l10n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CLDR plural categories.
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// pluralRule returns the plural category for the operands of a number.
+type pluralRule func(o pluralOperands) string
+
+// pluralRules are the CLDR cardinal plural rules by language, the languages of Alexa and a few neighbours.
+var pluralRules = map[string]pluralRule{
+	"ar": pluralArabic,
+	"de": pluralOneInteger,
+	"en": pluralOneInteger,
+	"es": pluralSpanish,
+	"fr": pluralFrench,
+	"hi": pluralHindi,
+	"it": pluralItalian,
+	"ja": pluralNone,
+	"ko": pluralNone,
+	"nl": pluralOneInteger,
+	"pl": pluralPolish,
+	"pt": pluralFrench,
+	"ru": pluralRussian,
+	"sv": pluralOneInteger,
+	"uk": pluralRussian,
+	"zh": pluralNone,
+}
+
+// PluralCategory returns the CLDR plural category of the number in the locale, e.g. "one" or "few".
+//
+// Languages without known rules use the English rules.
+func PluralCategory(locale string, n float64) string {
+	rule, ok := pluralRules[language(locale)]
+	if !ok {
+		rule = pluralOneInteger
+	}
+	return rule(newPluralOperands(n))
+}
+
+// pluralOperands are the CLDR plural operands: the absolute value n, its integer digits i,
+// the number of visible fraction digits v and the visible fraction digits f.
+type pluralOperands struct {
+	n float64
+	i int64
+	v int
+	f int64
+}
+
+func newPluralOperands(n float64) pluralOperands {
+	n = math.Abs(n)
+	o := pluralOperands{n: n, i: int64(n)}
+	s := strconv.FormatFloat(n, 'f', -1, 64)
+	if i := strings.Index(s, "."); i >= 0 {
+		frac := s[i+1:]
+		o.v = len(frac)
+		o.f, _ = strconv.ParseInt(frac, 10, 64)
+	}
+	return o
+}
+
+// integer returns true if the number has no visible fraction digits.
+func (o pluralOperands) integer() bool {
+	return o.v == 0
+}
+
+func inRange(n, from, to int64) bool {
+	return n >= from && n <= to
+}
+
+func pluralNone(pluralOperands) string {
+	return PluralOther
+}
+
+// pluralOneInteger is "one: i = 1 and v = 0", e.g. English and German.
+func pluralOneInteger(o pluralOperands) string {
+	if o.i == 1 && o.integer() {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralMillions is "many: e = 0 and i != 0 and i % 1000000 = 0 and v = 0" of Romance languages.
+func pluralMillions(o pluralOperands) bool {
+	return o.i != 0 && o.i%1000000 == 0 && o.integer()
+}
+
+func pluralSpanish(o pluralOperands) string {
+	switch {
+	case o.n == 1:
+		return PluralOne
+	case pluralMillions(o):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// pluralFrench is "one: i = 0,1", also used for Portuguese.
+func pluralFrench(o pluralOperands) string {
+	switch {
+	case o.i == 0 || o.i == 1:
+		return PluralOne
+	case pluralMillions(o):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+func pluralItalian(o pluralOperands) string {
+	switch {
+	case o.i == 1 && o.integer():
+		return PluralOne
+	case pluralMillions(o):
+		return PluralMany
+	}
+	return PluralOther
+}
+
+func pluralHindi(o pluralOperands) string {
+	if o.i == 0 || o.n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralRussian(o pluralOperands) string {
+	if !o.integer() {
+		return PluralOther
+	}
+	switch i10, i100 := o.i%10, o.i%100; {
+	case i10 == 1 && i100 != 11:
+		return PluralOne
+	case inRange(i10, 2, 4) && !inRange(i100, 12, 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+func pluralPolish(o pluralOperands) string {
+	if !o.integer() {
+		return PluralOther
+	}
+	switch i10, i100 := o.i%10, o.i%100; {
+	case o.i == 1:
+		return PluralOne
+	case inRange(i10, 2, 4) && !inRange(i100, 12, 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+func pluralArabic(o pluralOperands) string {
+	if !o.integer() {
+		return PluralOther
+	}
+	switch n100 := o.i % 100; {
+	case o.i == 0:
+		return PluralZero
+	case o.i == 1:
+		return PluralOne
+	case o.i == 2:
+		return PluralTwo
+	case inRange(n100, 3, 10):
+		return PluralFew
+	case inRange(n100, 11, 99):
+		return PluralMany
+	}
+	return PluralOther
+}
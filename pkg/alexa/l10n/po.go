@@ -0,0 +1,177 @@
+package l10n
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPO is returned for PO files that cannot be parsed.
+var ErrInvalidPO = errors.New("invalid PO file")
+
+// WritePO writes the translation as gettext PO file, the unit ID is the message context.
+//
+// Units missing in the target locale have an empty msgstr and a comment. Units without source are
+// left out, their empty msgid would collide with the header.
+func WritePO(w io.Writer, t *Translation) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, `msgid ""`)
+	fmt.Fprintln(bw, `msgstr ""`)
+	fmt.Fprintln(bw, `"Content-Type: text/plain; charset=UTF-8\n"`)
+	fmt.Fprintf(bw, "\"Language: %s\\n\"\n", t.TargetLocale)
+	fmt.Fprintf(bw, "\"X-Source-Language: %s\\n\"\n", t.SourceLocale)
+
+	for _, u := range t.Units {
+		if u.Source == "" {
+			continue
+		}
+		fmt.Fprintln(bw)
+		if u.Missing {
+			fmt.Fprintf(bw, "#. missing in %s\n", t.TargetLocale)
+		}
+		if strings.Contains(u.Source, "%") || strings.Contains(u.Target, "%") {
+			fmt.Fprintln(bw, "#, c-format")
+		}
+		writePOString(bw, "msgctxt", u.ID())
+		writePOString(bw, "msgid", u.Source)
+		writePOString(bw, "msgstr", u.Target)
+	}
+
+	return bw.Flush()
+}
+
+// writePOString writes the keyword with the quoted string, multi-line strings are split after each newline.
+func writePOString(w io.Writer, keyword, s string) {
+	if !strings.Contains(s, "\n") || strings.Index(s, "\n") == len(s)-1 {
+		fmt.Fprintf(w, "%s %s\n", keyword, quotePO(s))
+		return
+	}
+
+	fmt.Fprintf(w, "%s \"\"\n", keyword)
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if line == "" {
+			continue
+		}
+		fmt.Fprintln(w, quotePO(line))
+	}
+}
+
+func quotePO(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// ReadPO reads a translation from a gettext PO file written by WritePO.
+func ReadPO(r io.Reader) (*Translation, error) { //nolint:gocognit,cyclop
+	t := &Translation{}
+
+	var (
+		entry   = map[string]string{}
+		missing bool
+		keyword string
+		lineNo  int
+	)
+	flush := func() error {
+		defer func() {
+			entry = map[string]string{}
+			missing = false
+			keyword = ""
+		}()
+
+		ctx, ok := entry["msgctxt"]
+		if !ok {
+			// header
+			if _, ok := entry["msgid"]; ok {
+				parsePOHeader(t, entry["msgstr"])
+			}
+			return nil
+		}
+		key, idx, err := parseUnitID(ctx)
+		if err != nil {
+			return err
+		}
+		t.Units = append(t.Units, TranslationUnit{
+			Key:     key,
+			Index:   idx,
+			Source:  entry["msgid"],
+			Target:  entry["msgstr"],
+			Missing: missing && entry["msgstr"] == "",
+		})
+		return nil
+	}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		lineNo++
+		line := strings.TrimSpace(s.Text())
+
+		// a comment or keyword after msgstr starts the next entry, even without blank line
+		if _, ok := entry["msgstr"]; ok && line != "" && !strings.HasPrefix(line, `"`) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case line == "":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(line, "#. missing"):
+			missing = true
+		case strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, `"`):
+			if keyword == "" {
+				return nil, fmt.Errorf("%w: line %d: string without keyword", ErrInvalidPO, lineNo)
+			}
+			v, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidPO, lineNo, err)
+			}
+			entry[keyword] += v
+		default:
+			i := strings.Index(line, " ")
+			if i < 0 {
+				return nil, fmt.Errorf("%w: line %d: %s", ErrInvalidPO, lineNo, line)
+			}
+			keyword = line[:i]
+			switch keyword {
+			case "msgctxt", "msgid", "msgstr":
+			default:
+				return nil, fmt.Errorf("%w: line %d: unsupported keyword %s", ErrInvalidPO, lineNo, keyword)
+			}
+			v, err := strconv.Unquote(strings.TrimSpace(line[i+1:]))
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidPO, lineNo, err)
+			}
+			entry[keyword] = v
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parsePOHeader sets the locales of the translation from the header.
+func parsePOHeader(t *Translation, header string) {
+	for _, line := range strings.Split(header, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch strings.TrimSpace(parts[0]) {
+		case "Language":
+			t.TargetLocale = strings.TrimSpace(parts[1])
+		case "X-Source-Language":
+			t.SourceLocale = strings.TrimSpace(parts[1])
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package l10n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hamba/pkg/log"
+	"github.com/hamba/pkg/stats"
+)
+
+// Reload errors.
+var (
+	ErrReadOnly      = errors.New("reloadable registry is read-only")
+	ErrInvalidBundle = errors.New("invalid locale bundle")
+)
+
+// ReloadConfig contains the options for a ReloadableRegistry.
+type ReloadConfig struct {
+	DefaultLocale string
+	RegistryOpts  []RegistryOptFunc
+	Validate      func(r LocaleRegistry) error
+	Stats         stats.Statable
+	Logger        log.Loggable
+}
+
+// ReloadOptFunc defines the functions to be passed to NewReloadableRegistry.
+type ReloadOptFunc func(cfg *ReloadConfig)
+
+// WithDefaultLocale sets the default locale of every bundle, which must contain it.
+func WithDefaultLocale(locale string) ReloadOptFunc {
+	return func(cfg *ReloadConfig) {
+		cfg.DefaultLocale = locale
+	}
+}
+
+// WithRegistryOpts sets the options of the registries created for every bundle.
+func WithRegistryOpts(opts ...RegistryOptFunc) ReloadOptFunc {
+	return func(cfg *ReloadConfig) {
+		cfg.RegistryOpts = opts
+	}
+}
+
+// WithValidator replaces the validation of new bundles, see ValidateBundle.
+func WithValidator(fn func(r LocaleRegistry) error) ReloadOptFunc {
+	return func(cfg *ReloadConfig) {
+		cfg.Validate = fn
+	}
+}
+
+// WithStats reports the version of the loaded bundle as gauge "l10n.bundle.version".
+func WithStats(sable stats.Statable) ReloadOptFunc {
+	return func(cfg *ReloadConfig) {
+		cfg.Stats = sable
+	}
+}
+
+// WithLogger logs reloads and their errors while watching.
+func WithLogger(lable log.Loggable) ReloadOptFunc {
+	return func(cfg *ReloadConfig) {
+		cfg.Logger = lable
+	}
+}
+
+// bundle is an immutable set of locales loaded from a directory.
+type bundle struct {
+	registry *Registry
+	version  int64
+	sum      string
+}
+
+// ReloadableRegistry is a registry of the locale files in a directory, which can be reloaded while in use.
+//
+// Every (re)load creates new locales and swaps them in at once, the locales in use are never modified.
+// Locales resolved before a reload keep the translations of the previous bundle.
+type ReloadableRegistry struct {
+	fsys fs.FS
+	dir  string
+	cfg  ReloadConfig
+
+	mu      sync.Mutex // serializes reloads
+	current atomic.Value
+}
+
+// NewReloadableRegistry returns a registry of the locale files in dir of fsys, e.g. os.DirFS("loca/locales").
+func NewReloadableRegistry(fsys fs.FS, dir string, opts ...ReloadOptFunc) (*ReloadableRegistry, error) {
+	cfg := ReloadConfig{Validate: ValidateBundle}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	r := &ReloadableRegistry{fsys: fsys, dir: dir, cfg: cfg}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ValidateBundle fails for bundles without locales and for lint issues other than missing keys, see Lint.
+func ValidateBundle(r LocaleRegistry) error {
+	if len(r.GetLocales()) == 0 {
+		return errors.New("no locales")
+	}
+
+	var msgs []string
+	for _, i := range Lint(r) {
+		if i.Type != LintMissingKey {
+			msgs = append(msgs, i.Error())
+		}
+	}
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// Reload loads, validates and swaps in the locale files, the current bundle is kept on errors.
+func (r *ReloadableRegistry) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sum, version, err := r.checksum()
+	if err != nil {
+		return err
+	}
+	reg, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	r.current.Store(&bundle{registry: reg, version: version, sum: sum})
+	stats.Gauge(r.stats(), "l10n.bundle.version", float64(version), 1.0)
+	return nil
+}
+
+func (r *ReloadableRegistry) load() (*Registry, error) {
+	locales, err := LoadFS(r.fsys, r.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	reg, _ := NewRegistry(r.cfg.RegistryOpts...).(*Registry)
+	for _, l := range locales {
+		if err := reg.Register(l); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+		}
+	}
+	if r.cfg.DefaultLocale != "" {
+		if err := reg.SetDefault(r.cfg.DefaultLocale); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+		}
+	}
+	if r.cfg.Validate != nil {
+		if err := r.cfg.Validate(reg); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+		}
+	}
+	return reg, nil
+}
+
+// checksum returns a checksum of the names, sizes and modification times of the files
+// and the newest modification time as version.
+func (r *ReloadableRegistry) checksum() (string, int64, error) {
+	entries, err := fs.ReadDir(r.fsys, r.dir)
+	if err != nil {
+		return "", 0, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	var version int64
+	var b strings.Builder
+	for _, e := range entries {
+		if e.IsDir() || decoders[strings.ToLower(path.Ext(e.Name()))] == nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return "", 0, err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", e.Name(), info.Size(), info.ModTime().UnixNano())
+		if v := info.ModTime().Unix(); v > version {
+			version = v
+		}
+	}
+	return b.String(), version, nil
+}
+
+// Watch checks the directory for changes every interval and reloads it, until the context is done.
+func (r *ReloadableRegistry) Watch(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		sum, _, err := r.checksum()
+		if err != nil {
+			log.Error(r.logger(), "could not check locales: "+err.Error(), "dir", r.dir)
+			continue
+		}
+		if sum == r.bundle().sum {
+			continue
+		}
+
+		if err := r.Reload(); err != nil {
+			log.Error(r.logger(), "could not reload locales: "+err.Error(), "dir", r.dir)
+			continue
+		}
+		log.Info(r.logger(), "reloaded locales", "dir", r.dir, "version", r.Version())
+	}
+}
+
+// Version returns the version of the loaded bundle, the newest modification time of its files in unix seconds.
+func (r *ReloadableRegistry) Version() int64 {
+	return r.bundle().version
+}
+
+func (r *ReloadableRegistry) bundle() *bundle {
+	b, _ := r.current.Load().(*bundle)
+	return b
+}
+
+func (r *ReloadableRegistry) stats() stats.Statable {
+	if r.cfg.Stats == nil {
+		return nullStatable{}
+	}
+	return r.cfg.Stats
+}
+
+func (r *ReloadableRegistry) logger() log.Loggable {
+	if r.cfg.Logger == nil {
+		return nullLoggable{}
+	}
+	return r.cfg.Logger
+}
+
+// Register fails, locales are registered from the files only.
+func (r *ReloadableRegistry) Register(l LocaleInstance, _ ...RegisterFunc) error {
+	return fmt.Errorf("%w: cannot register locale %s", ErrReadOnly, l.GetName())
+}
+
+// Resolve returns a new instance of the Locale matching the given name from the current bundle.
+func (r *ReloadableRegistry) Resolve(locale string) (LocaleInstance, error) {
+	return r.bundle().registry.Resolve(locale)
+}
+
+// GetDefault returns a new instance of the default locale of the current bundle.
+func (r *ReloadableRegistry) GetDefault() LocaleInstance {
+	return r.bundle().registry.GetDefault()
+}
+
+// SetDefault fails, the default locale is set with WithDefaultLocale.
+func (r *ReloadableRegistry) SetDefault(locale string) error {
+	return fmt.Errorf("%w: cannot set default locale %s", ErrReadOnly, locale)
+}
+
+// GetLocales returns the locales of the current bundle.
+func (r *ReloadableRegistry) GetLocales() map[string]LocaleInstance {
+	return r.bundle().registry.GetLocales()
+}
+
+type nullStatable struct{}
+
+func (nullStatable) Statter() stats.Statter {
+	return stats.Null
+}
+
+type nullLoggable struct{}
+
+func (nullLoggable) Logger() log.Logger {
+	return log.Null
+}
@@ -0,0 +1,143 @@
+package l10n_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/hamba/pkg/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestBundle(title string, mod time.Time) fstest.MapFS {
+	return fstest.MapFS{
+		"locales/en-US.yaml": {Data: []byte("Launch_Title: " + title + "\nLaunch_SSML: <speak>Hi</speak>\n"), ModTime: mod},
+		"locales/de-DE.json": {Data: []byte(`{"Launch_Title": "Hallo"}`), ModTime: mod.Add(-time.Hour)},
+		"locales/README.md":  {Data: []byte("# Locales"), ModTime: mod.Add(time.Hour)},
+	}
+}
+
+type lockFS struct {
+	fsys fs.FS
+	mu   *sync.Mutex
+}
+
+func (l lockFS) Open(name string) (fs.File, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.fsys.Open(name)
+}
+
+func TestReloadableRegistry(t *testing.T) {
+	mod := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	fsys := newTestBundle("Hello", mod)
+	s := new(mockStats)
+	s.On("Gauge", "l10n.bundle.version", float64(mod.Unix()), float32(1.0), []string(nil)).Once()
+	sable := stats.NewMockStatable(s)
+
+	r, err := l10n.NewReloadableRegistry(fsys, "locales", l10n.WithDefaultLocale("en-US"), l10n.WithStats(sable))
+
+	assert.NoError(t, err)
+	assert.Equal(t, mod.Unix(), r.Version())
+	assert.Len(t, r.GetLocales(), 2)
+	assert.Equal(t, "en-US", r.GetDefault().GetName())
+	loc, err := r.Resolve("de-AT")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hallo", loc.Get(l10n.KeyLaunchTitle))
+	s.AssertExpectations(t)
+
+	assert.True(t, errors.Is(r.Register(l10n.NewLocale("fr-FR")), l10n.ErrReadOnly))
+	assert.True(t, errors.Is(r.SetDefault("de-DE"), l10n.ErrReadOnly))
+}
+
+func TestReloadableRegistry_Reload(t *testing.T) {
+	mod := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	fsys := newTestBundle("Hello", mod)
+	r, err := l10n.NewReloadableRegistry(fsys, "locales", l10n.WithDefaultLocale("en-US"))
+	assert.NoError(t, err)
+	before := r.GetDefault()
+	en := r.GetLocales()["en-US"].(*l10n.Locale)
+
+	for k, v := range newTestBundle("Howdy", mod.Add(time.Minute)) {
+		fsys[k] = v
+	}
+	assert.NoError(t, r.Reload())
+
+	assert.Equal(t, "Howdy", r.GetDefault().Get(l10n.KeyLaunchTitle))
+	assert.Equal(t, mod.Add(time.Minute).Unix(), r.Version())
+	// swapped, not modified
+	assert.Equal(t, "Hello", before.Get(l10n.KeyLaunchTitle))
+	assert.Equal(t, "Hello", en.Get(l10n.KeyLaunchTitle))
+}
+
+func TestReloadableRegistry_InvalidBundle(t *testing.T) {
+	mod := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	fsys := newTestBundle("Hello", mod)
+	r, err := l10n.NewReloadableRegistry(fsys, "locales", l10n.WithDefaultLocale("en-US"))
+	assert.NoError(t, err)
+
+	fsys["locales/en-US.yaml"] = &fstest.MapFile{Data: []byte("Launch_SSML: <speak>Hi"), ModTime: mod.Add(time.Minute)}
+	err = r.Reload()
+	assert.True(t, errors.Is(err, l10n.ErrInvalidBundle))
+	assert.Equal(t, "Hello", r.GetDefault().Get(l10n.KeyLaunchTitle))
+	assert.Equal(t, mod.Unix(), r.Version())
+
+	fsys["locales/en-US.yaml"] = &fstest.MapFile{Data: []byte("Launch_SSML: ["), ModTime: mod.Add(time.Minute)}
+	assert.Error(t, r.Reload())
+
+	delete(fsys, "locales/en-US.yaml")
+	assert.True(t, errors.Is(r.Reload(), l10n.ErrInvalidBundle))
+
+	_, err = l10n.NewReloadableRegistry(fstest.MapFS{}, ".")
+	assert.True(t, errors.Is(err, l10n.ErrInvalidBundle))
+}
+
+func TestReloadableRegistry_Watch(t *testing.T) {
+	mod := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	fsys := newTestBundle("Hello", mod)
+	// the map is not safe to modify while watching
+	var mu sync.Mutex
+	r, err := l10n.NewReloadableRegistry(lockFS{fsys: fsys, mu: &mu}, "locales", l10n.WithDefaultLocale("en-US"))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx, time.Millisecond)
+
+	mu.Lock()
+	fsys["locales/en-US.yaml"] = &fstest.MapFile{Data: []byte("Launch_Title: Howdy"), ModTime: mod.Add(time.Minute)}
+	mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		return r.GetDefault().Get(l10n.KeyLaunchTitle) == "Howdy"
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, mod.Add(time.Minute).Unix(), r.Version())
+}
+
+type mockStats struct {
+	mock.Mock
+}
+
+func (m *mockStats) Inc(name string, value int64, rate float32, tags ...string) {
+	m.Called(name, value, rate, tags)
+}
+
+func (m *mockStats) Gauge(name string, value float64, rate float32, tags ...string) {
+	m.Called(name, value, rate, tags)
+}
+
+func (m *mockStats) Timing(name string, value time.Duration, rate float32, tags ...string) {
+	m.Called(name, value, rate, tags)
+}
+
+func (m *mockStats) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
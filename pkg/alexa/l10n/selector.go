@@ -0,0 +1,160 @@
+package l10n
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Selector selects which of the n variants of a key GetAny returns.
+type Selector interface {
+	Select(key string, n int) int
+}
+
+// SelectorFunc is an adapter to use a function as Selector.
+type SelectorFunc func(key string, n int) int
+
+// Select calls f(key, n).
+func (f SelectorFunc) Select(key string, n int) int {
+	return f(key, n)
+}
+
+// SelectorSetter is implemented by locales with an injectable Selector.
+type SelectorSetter interface {
+	SetSelector(s Selector)
+}
+
+// defaultSelector selects variants at random from the global source.
+var defaultSelector = SelectorFunc(func(_ string, n int) int {
+	return rand.Intn(n) //nolint:gosec
+})
+
+// RandomSelector selects variants at random from its own source.
+type RandomSelector struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewRandomSelector returns a random Selector with the given seed, the same seed selects the same variants.
+func NewRandomSelector(seed int64) *RandomSelector {
+	return &RandomSelector{rnd: rand.New(rand.NewSource(seed))} //nolint:gosec
+}
+
+// Select returns a random variant.
+func (s *RandomSelector) Select(_ string, n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rnd.Intn(n)
+}
+
+// RoundRobinSelector selects the variants of each key in turn, starting with the first.
+type RoundRobinSelector struct {
+	mu   sync.Mutex
+	next map[string]int
+}
+
+// NewRoundRobinSelector returns a round-robin Selector.
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{next: map[string]int{}}
+}
+
+// Select returns the variant after the one returned before for the key.
+func (s *RoundRobinSelector) Select(key string, n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.next[key] % n
+	s.next[key] = i + 1
+	return i
+}
+
+// History stores the variants recently used of a key, e.g. in the session attributes.
+type History interface {
+	Recent(key string) []int
+	SetRecent(key string, variants []int)
+}
+
+// MemoryHistory is an in-memory History, e.g. for tests. It is not keyed by session,
+// use a History of the session instead for concurrent sessions.
+type MemoryHistory struct {
+	mu     sync.Mutex
+	recent map[string][]int
+}
+
+// NewMemoryHistory returns an empty in-memory History.
+func NewMemoryHistory() *MemoryHistory {
+	return &MemoryHistory{recent: map[string][]int{}}
+}
+
+// Recent returns the recently used variants of the key.
+func (h *MemoryHistory) Recent(key string) []int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.recent[key]
+}
+
+// SetRecent sets the recently used variants of the key.
+func (h *MemoryHistory) SetRecent(key string, variants []int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.recent[key] = variants
+}
+
+// RecentSelector avoids the variants used last for a key.
+//
+// Its state is the history, which should belong to a single session. Set it on the request-scoped locale
+// with SetSelector, not on the registry with WithSelector, as the registry is shared by all sessions.
+type RecentSelector struct {
+	history History
+	last    int
+	sel     Selector
+}
+
+// NewRecentSelector returns a Selector that avoids the last variants used for a key, as recorded in the history.
+//
+// It selects among the other variants with sel, at random if sel is nil. Keys with no more than last variants
+// avoid as many as possible, so a key with two variants alternates. A negative last avoids none.
+func NewRecentSelector(h History, last int, sel Selector) *RecentSelector {
+	if last < 0 {
+		last = 0
+	}
+	if sel == nil {
+		sel = defaultSelector
+	}
+	return &RecentSelector{history: h, last: last, sel: sel}
+}
+
+// Select returns a variant not used recently and adds it to the history.
+func (s *RecentSelector) Select(key string, n int) int {
+	recent := s.history.Recent(key)
+	last := s.last
+	if last >= n {
+		last = n - 1
+	}
+	if len(recent) > last {
+		recent = recent[len(recent)-last:]
+	}
+
+	used := map[int]bool{}
+	for _, i := range recent {
+		used[i] = true
+	}
+	candidates := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		if !used[i] {
+			candidates = append(candidates, i)
+		}
+	}
+
+	i := candidates[s.sel.Select(key, len(candidates))]
+	if s.last > 0 {
+		recent = append(append([]int{}, recent...), i)
+		if len(recent) > s.last {
+			recent = recent[len(recent)-s.last:]
+		}
+		s.history.SetRecent(key, recent)
+	}
+	return i
+}
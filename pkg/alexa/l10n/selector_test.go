@@ -0,0 +1,78 @@
+package l10n_test
+
+import (
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/stretchr/testify/assert"
+)
+
+func selectN(s l10n.Selector, key string, n, times int) []int {
+	var got []int
+	for i := 0; i < times; i++ {
+		got = append(got, s.Select(key, n))
+	}
+	return got
+}
+
+func TestRandomSelector(t *testing.T) {
+	a := selectN(l10n.NewRandomSelector(42), "Key", 5, 20)
+	b := selectN(l10n.NewRandomSelector(42), "Key", 5, 20)
+
+	assert.Equal(t, a, b)
+	for _, i := range a {
+		assert.True(t, i >= 0 && i < 5)
+	}
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	s := l10n.NewRoundRobinSelector()
+
+	assert.Equal(t, []int{0, 1, 2, 0}, selectN(s, "Key", 3, 4))
+	assert.Equal(t, []int{0, 1, 0}, selectN(s, "Other", 2, 3))
+	assert.Equal(t, []int{1}, selectN(s, "Key", 3, 1))
+}
+
+func TestRecentSelector(t *testing.T) {
+	h := l10n.NewMemoryHistory()
+	s := l10n.NewRecentSelector(h, 2, l10n.NewRandomSelector(1))
+
+	got := selectN(s, "Key", 3, 30)
+	for i := 2; i < len(got); i++ {
+		assert.NotContains(t, got[i-2:i], got[i])
+	}
+	assert.Len(t, h.Recent("Key"), 2)
+
+	// two variants alternate
+	got = selectN(s, "Two", 2, 4)
+	assert.NotEqual(t, got[0], got[1])
+	assert.Equal(t, got[0], got[2])
+	assert.Equal(t, []int{0, 0, 0}, selectN(s, "One", 1, 3))
+}
+
+func TestRecentSelector_NegativeLast(t *testing.T) {
+	h := l10n.NewMemoryHistory()
+	h.SetRecent("Key", []int{0, 1})
+	s := l10n.NewRecentSelector(h, -1, l10n.NewRoundRobinSelector())
+
+	assert.Equal(t, []int{0, 1, 2}, selectN(s, "Key", 3, 3))
+	assert.Equal(t, []int{0, 1}, h.Recent("Key"))
+}
+
+func TestLocale_GetAnySelector(t *testing.T) {
+	l := l10n.NewLocale("en-US")
+	l.Set("Greeting", []string{"Hi %s", "Hello %s", "Howdy %s"})
+	l.SetSelector(l10n.NewRoundRobinSelector())
+
+	assert.Equal(t, "Hi Ann", l.GetAny("Greeting", "Ann"))
+	assert.Equal(t, "Hello Ann", l.GetAny("Greeting", "Ann"))
+
+	r := l10n.NewRegistry(l10n.WithSelector(l10n.SelectorFunc(func(_ string, n int) int {
+		return n - 1
+	})))
+	assert.NoError(t, r.Register(l))
+	loc, err := r.Resolve("en-US")
+	assert.NoError(t, err)
+	assert.Equal(t, "Howdy Bob", loc.GetAny("Greeting", "Bob"))
+	assert.Equal(t, "Howdy Bob", r.GetDefault().GetAny("Greeting", "Bob"))
+}
@@ -0,0 +1,112 @@
+package l10n
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidUnitID is returned for translation units with an ID not like "Key[0]".
+var ErrInvalidUnitID = errors.New("invalid translation unit id")
+
+// TranslationUnit is a single variant of a key, translated from the source into the target locale.
+type TranslationUnit struct {
+	Key    string
+	Index  int
+	Source string
+	Target string
+	// Missing is true if the key or variant is missing in the target locale.
+	Missing bool
+}
+
+// ID returns the unique ID of the unit, e.g. "Launch_SSML[1]".
+func (u TranslationUnit) ID() string {
+	return fmt.Sprintf("%s[%d]", u.Key, u.Index)
+}
+
+// parseUnitID returns the key and index of a unit ID.
+func parseUnitID(id string) (string, int, error) {
+	i := strings.LastIndex(id, "[")
+	if i <= 0 || !strings.HasSuffix(id, "]") {
+		return "", 0, fmt.Errorf("%w: %s", ErrInvalidUnitID, id)
+	}
+	idx, err := strconv.Atoi(id[i+1 : len(id)-1])
+	if err != nil || idx < 0 {
+		return "", 0, fmt.Errorf("%w: %s", ErrInvalidUnitID, id)
+	}
+	return id[:i], idx, nil
+}
+
+// Translation contains the units to translate a source into a target locale.
+type Translation struct {
+	SourceLocale string
+	TargetLocale string
+	Units        []TranslationUnit
+}
+
+// NewTranslation returns the units of all keys of both locales, sorted by key.
+//
+// Every variant is a unit of its own, keys and variants missing in target are marked as Missing.
+func NewTranslation(source, target *Locale) *Translation {
+	keys := map[string]bool{}
+	for k := range source.TextSnippets {
+		keys[k] = true
+	}
+	for k := range target.TextSnippets {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	t := &Translation{SourceLocale: source.Name, TargetLocale: target.Name}
+	for _, k := range sorted {
+		src, tgt := source.TextSnippets[k], target.TextSnippets[k]
+		n := len(src)
+		if len(tgt) > n {
+			n = len(tgt)
+		}
+		for i := 0; i < n; i++ {
+			u := TranslationUnit{Key: k, Index: i, Missing: i >= len(tgt)}
+			if i < len(src) {
+				u.Source = src[i]
+			}
+			if i < len(tgt) {
+				u.Target = tgt[i]
+			}
+			t.Units = append(t.Units, u)
+		}
+	}
+	return t
+}
+
+// Locale returns the target locale, see Merge.
+func (t *Translation) Locale() *Locale {
+	return t.Merge(NewLocale(t.TargetLocale))
+}
+
+// Merge sets the targets of the units in the locale and returns it.
+//
+// Keys and variants without a unit are kept, units without target leave the variant unchanged.
+// Variants keep their index, gaps before a translated variant are filled with empty variants.
+func (t *Translation) Merge(l *Locale) *Locale {
+	if l.TextSnippets == nil {
+		l.TextSnippets = Snippets{}
+	}
+	for _, u := range t.Units {
+		if u.Target == "" {
+			continue
+		}
+		variants := l.TextSnippets[u.Key]
+		for len(variants) <= u.Index {
+			variants = append(variants, "")
+		}
+		variants[u.Index] = u.Target
+		l.TextSnippets[u.Key] = variants
+	}
+	return l
+}
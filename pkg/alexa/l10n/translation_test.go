@@ -0,0 +1,124 @@
+package l10n_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTranslation() *l10n.Translation {
+	en := l10n.NewLocale("en-US")
+	en.Set("Greeting", []string{"Hi", "Hello", "Howdy"})
+	en.Set("Error_Text", []string{"The following error occurred:\n%s"})
+	en.Set("Quote", []string{`<speak>Say "cheese" \o/</speak>`})
+	en.Set("Only_EN", []string{"only english"})
+	de := l10n.NewLocale("de-DE")
+	de.Set("Greeting", []string{"Hallo", "Servus"})
+	de.Set("Error_Text", []string{"Folgender Fehler ist aufgetreten:\n%s"})
+	de.Set("Quote", []string{`<speak>Sag "Käse" \o/</speak>`})
+	de.Set("Only_DE", []string{"nur deutsch"})
+
+	return l10n.NewTranslation(en, de)
+}
+
+func TestNewTranslation(t *testing.T) {
+	tr := newTestTranslation()
+
+	assert.Equal(t, "en-US", tr.SourceLocale)
+	assert.Equal(t, "de-DE", tr.TargetLocale)
+	assert.Len(t, tr.Units, 7)
+	assert.Equal(t, "Error_Text[0]", tr.Units[0].ID())
+
+	var missing []string
+	for _, u := range tr.Units {
+		if u.Missing {
+			missing = append(missing, u.ID())
+		}
+	}
+	assert.Equal(t, []string{"Greeting[2]", "Only_EN[0]"}, missing)
+
+	l := tr.Locale()
+	assert.Equal(t, "de-DE", l.GetName())
+	assert.Equal(t, []string{"Hallo", "Servus"}, l.GetAll("Greeting"))
+	assert.Equal(t, "nur deutsch", l.Get("Only_DE"))
+	assert.Empty(t, l.TextSnippets["Only_EN"])
+}
+
+func TestTranslation_Merge(t *testing.T) {
+	tr := &l10n.Translation{
+		TargetLocale: "de-DE",
+		Units: []l10n.TranslationUnit{
+			{Key: "Greeting", Index: 0, Source: "Hi", Target: "Hallo"},
+			{Key: "Greeting", Index: 1, Source: "Hello", Missing: true},
+			{Key: "Greeting", Index: 2, Source: "Howdy", Target: "Servus"},
+			{Key: "Bye", Index: 0, Source: "Bye", Missing: true},
+		},
+	}
+
+	l := tr.Locale()
+	assert.Equal(t, []string{"Hallo", "", "Servus"}, l.GetAll("Greeting"))
+	assert.Empty(t, l.TextSnippets["Bye"])
+
+	de := l10n.NewLocale("de-DE")
+	de.Set("Greeting", []string{"Moin", "Guten Tag"})
+	de.Set("Bye", []string{"Tschüss"})
+	de.Set("Only_DE", []string{"nur deutsch"})
+
+	l = tr.Merge(de)
+	assert.Equal(t, []string{"Hallo", "Guten Tag", "Servus"}, l.GetAll("Greeting"))
+	assert.Equal(t, []string{"Tschüss"}, l.GetAll("Bye"))
+	assert.Equal(t, []string{"nur deutsch"}, l.GetAll("Only_DE"))
+}
+
+func TestXLIFF_RoundTrip(t *testing.T) {
+	tr := newTestTranslation()
+
+	var buf bytes.Buffer
+	err := l10n.WriteXLIFF(&buf, tr)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">`)
+	assert.Contains(t, buf.String(), `<target state="needs-translation"></target>`)
+
+	got, err := l10n.ReadXLIFF(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, tr, got)
+}
+
+func TestPO_RoundTrip(t *testing.T) {
+	tr := newTestTranslation()
+
+	var buf bytes.Buffer
+	err := l10n.WritePO(&buf, tr)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "#. missing in de-DE\nmsgctxt \"Greeting[2]\"\nmsgid \"Howdy\"\nmsgstr \"\"\n")
+	assert.Contains(t, buf.String(), "#, c-format\nmsgctxt \"Error_Text[0]\"\nmsgid \"\"\n\"The following error occurred:\\n\"\n\"%s\"\n")
+
+	assert.Equal(t, 1, strings.Count(buf.String(), "msgid \"\"\nmsgstr"))
+	assert.NotContains(t, buf.String(), "Only_DE")
+
+	got, err := l10n.ReadPO(&buf)
+	assert.NoError(t, err)
+	want := *tr
+	want.Units = append(tr.Units[:4:4], tr.Units[5:]...)
+	assert.Equal(t, &want, got)
+}
+
+func TestReadPO_Errors(t *testing.T) {
+	tests := []string{
+		"msgctxt \"Greeting\"\nmsgid \"Hi\"\nmsgstr \"Hallo\"\n",
+		"msgid \"Hi\nmsgstr \"Hallo\"\n",
+		"msgid_plural \"Hi\"\n",
+		"\"Hi\"\n",
+	}
+	for _, in := range tests {
+		_, err := l10n.ReadPO(strings.NewReader(in))
+		assert.Error(t, err, in)
+	}
+
+	_, err := l10n.ReadPO(strings.NewReader("msgid_plural \"Hi\"\n"))
+	assert.True(t, errors.Is(err, l10n.ErrInvalidPO))
+}
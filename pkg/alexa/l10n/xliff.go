@@ -0,0 +1,107 @@
+package l10n
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// XLIFF target states.
+const (
+	XLIFFStateTranslated       = "translated"
+	XLIFFStateNeedsTranslation = "needs-translation"
+)
+
+type xliffDocument struct {
+	XMLName xml.Name  `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string    `xml:"version,attr"`
+	File    xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string           `xml:"original,attr"`
+	SourceLanguage string           `xml:"source-language,attr"`
+	TargetLanguage string           `xml:"target-language,attr"`
+	Datatype       string           `xml:"datatype,attr"`
+	Units          []xliffTransUnit `xml:"body>trans-unit"`
+}
+
+type xliffTransUnit struct {
+	ID      string       `xml:"id,attr"`
+	ResName string       `xml:"resname,attr,omitempty"`
+	Space   string       `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Source  string       `xml:"source"`
+	Target  *xliffTarget `xml:"target"`
+	Note    string       `xml:"note,omitempty"`
+}
+
+type xliffTarget struct {
+	State string `xml:"state,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+// WriteXLIFF writes the translation as XLIFF 1.2 document.
+//
+// Units missing in the target locale have the state "needs-translation".
+func WriteXLIFF(w io.Writer, t *Translation) error {
+	doc := xliffDocument{
+		Version: "1.2",
+		File: xliffFile{
+			Original:       t.TargetLocale,
+			SourceLanguage: t.SourceLocale,
+			TargetLanguage: t.TargetLocale,
+			Datatype:       "plaintext",
+		},
+	}
+	for _, u := range t.Units {
+		tu := xliffTransUnit{
+			ID:      u.ID(),
+			ResName: u.Key,
+			Space:   "preserve",
+			Source:  u.Source,
+			Target:  &xliffTarget{State: XLIFFStateTranslated, Text: u.Target},
+		}
+		if u.Missing {
+			tu.Target.State = XLIFFStateNeedsTranslation
+			tu.Note = fmt.Sprintf("missing in %s", t.TargetLocale)
+		}
+		doc.File.Units = append(doc.File.Units, tu)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadXLIFF reads a translation from a XLIFF 1.2 document.
+func ReadXLIFF(r io.Reader) (*Translation, error) {
+	var doc xliffDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	t := &Translation{
+		SourceLocale: doc.File.SourceLanguage,
+		TargetLocale: doc.File.TargetLanguage,
+	}
+	for _, tu := range doc.File.Units {
+		key, idx, err := parseUnitID(tu.ID)
+		if err != nil {
+			return nil, err
+		}
+		u := TranslationUnit{Key: key, Index: idx, Source: tu.Source, Missing: true}
+		if tu.Target != nil {
+			u.Target = tu.Target.Text
+			u.Missing = tu.Target.State == XLIFFStateNeedsTranslation && u.Target == ""
+		}
+		t.Units = append(t.Units, u)
+	}
+	return t, nil
+}
@@ -0,0 +1,220 @@
+package alexa
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// PersistenceAdapter loads and saves persistent attributes by ID.
+type PersistenceAdapter interface {
+	Load(id string) (map[string]interface{}, error)
+	Save(id string, attrs map[string]interface{}) error
+}
+
+// PersistenceID returns the ID to persist attributes for: the recognized person first, then the user.
+func (r *RequestEnvelope) PersistenceID() string {
+	if p, err := r.ContextPerson(); err == nil && p.PersonID != "" {
+		return p.PersonID
+	}
+	if u, err := r.ContextUser(); err == nil && u.UserID != "" {
+		return u.UserID
+	}
+	if r.Session != nil && r.Session.User != nil {
+		return r.Session.User.UserID
+	}
+	return ""
+}
+
+// PersistentState holds the persistent attributes of a user during a request.
+type PersistentState struct {
+	id      string
+	attrs   map[string]interface{}
+	changed bool
+}
+
+// NewPersistentState returns a state with the given attributes.
+func NewPersistentState(id string, attrs map[string]interface{}) *PersistentState {
+	s := &PersistentState{id: id, attrs: map[string]interface{}{}}
+	for k, v := range attrs {
+		s.attrs[k] = v
+	}
+	return s
+}
+
+// ID returns the ID the attributes belong to.
+func (s *PersistentState) ID() string {
+	return s.id
+}
+
+// Has returns true if the key is set.
+func (s *PersistentState) Has(key string) bool {
+	_, ok := s.attrs[key]
+	return ok
+}
+
+// Get decodes the value of the key into v.
+func (s *PersistentState) Get(key string, v interface{}) error {
+	val, ok := s.attrs[key]
+	if !ok {
+		return &NotFoundError{"persistent attribute", key}
+	}
+
+	return decodeAttribute(val, v)
+}
+
+// Set sets the key to the JSON representation of v.
+func (s *PersistentState) Set(key string, v interface{}) error {
+	val, err := encodeAttribute(v)
+	if err != nil {
+		return err
+	}
+
+	s.attrs[key] = val
+	s.changed = true
+	return nil
+}
+
+// Delete removes the key.
+func (s *PersistentState) Delete(key string) {
+	if _, ok := s.attrs[key]; !ok {
+		return
+	}
+	delete(s.attrs, key)
+	s.changed = true
+}
+
+// Changed returns true if the attributes were modified.
+func (s *PersistentState) Changed() bool {
+	return s.changed
+}
+
+// Attributes returns the persistent attributes.
+func (s *PersistentState) Attributes() map[string]interface{} {
+	return s.attrs
+}
+
+// WithPersistentState sets the persistent state for the handlers of the request.
+func (b *ResponseBuilder) WithPersistentState(s *PersistentState) *ResponseBuilder {
+	b.persistent = s
+	return b
+}
+
+// PersistentState returns the persistent state of the request.
+//
+// Without a persistence layer in the handler chain, changes are not saved.
+func (b *ResponseBuilder) PersistentState() *PersistentState {
+	if b.persistent == nil {
+		b.persistent = NewPersistentState("", nil)
+	}
+	return b.persistent
+}
+
+// MemoryPersistence keeps attributes in memory.
+type MemoryPersistence struct {
+	mu    sync.RWMutex
+	attrs map[string]map[string]interface{}
+}
+
+// NewMemoryPersistence returns an empty in-memory persistence.
+func NewMemoryPersistence() *MemoryPersistence {
+	return &MemoryPersistence{attrs: map[string]map[string]interface{}{}}
+}
+
+// Load returns a copy of the attributes stored for the ID.
+func (p *MemoryPersistence) Load(id string) (map[string]interface{}, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return copyAttributes(p.attrs[id]), nil
+}
+
+// Save stores a copy of the attributes for the ID.
+func (p *MemoryPersistence) Save(id string, attrs map[string]interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(attrs) == 0 {
+		delete(p.attrs, id)
+		return nil
+	}
+	p.attrs[id] = copyAttributes(attrs)
+	return nil
+}
+
+// FilePersistence keeps attributes of all IDs in a single JSON file.
+//
+// The file is replaced atomically on every save, it is meant for a single process.
+type FilePersistence struct {
+	mu   sync.Mutex
+	path string
+	mem  *MemoryPersistence
+}
+
+// NewFilePersistence returns a file persistence, loading existing attributes from path.
+func NewFilePersistence(path string) (*FilePersistence, error) {
+	p := &FilePersistence{path: path, mem: NewMemoryPersistence()}
+
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return p, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		if err := jsoniter.Unmarshal(data, &p.mem.attrs); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Load returns the attributes stored for the ID.
+func (p *FilePersistence) Load(id string) (map[string]interface{}, error) {
+	return p.mem.Load(id)
+}
+
+// Save stores the attributes for the ID and writes the file.
+func (p *FilePersistence) Save(id string, attrs map[string]interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.mem.Save(id, attrs); err != nil {
+		return err
+	}
+
+	p.mem.mu.RLock()
+	data, err := jsoniter.Marshal(p.mem.attrs)
+	p.mem.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(p.path), filepath.Base(p.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p.path)
+}
+
+func copyAttributes(attrs map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,98 @@
+package alexa
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistenceID(t *testing.T) {
+	r := &RequestEnvelope{}
+	assert.Empty(t, r.PersistenceID())
+
+	r.Session = &Session{User: &ContextUser{UserID: "session-user"}}
+	assert.Equal(t, "session-user", r.PersistenceID())
+
+	r.Context = &Context{System: &ContextSystem{User: &ContextUser{UserID: "user"}}}
+	assert.Equal(t, "user", r.PersistenceID())
+
+	r.Context.System.Person = &ContextSystemPerson{PersonID: "person"}
+	assert.Equal(t, "person", r.PersistenceID())
+}
+
+func TestPersistentState(t *testing.T) {
+	s := NewPersistentState("id", map[string]interface{}{"foo": "bar"})
+	assert.Equal(t, "id", s.ID())
+	assert.True(t, s.Has("foo"))
+	assert.False(t, s.Changed())
+
+	var foo string
+	err := s.Get("foo", &foo)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", foo)
+
+	err = s.Get("missing", &foo)
+	assert.Error(t, err)
+
+	s.Delete("missing")
+	assert.False(t, s.Changed())
+
+	err = s.Set("region", struct{ Name string }{"Frankfurt"})
+	assert.NoError(t, err)
+	assert.True(t, s.Changed())
+
+	s.Delete("foo")
+	assert.Equal(t, map[string]interface{}{"region": map[string]interface{}{"Name": "Frankfurt"}}, s.Attributes())
+
+	// builder always returns a state
+	b := &ResponseBuilder{}
+	assert.NotNil(t, b.PersistentState())
+	b.WithPersistentState(s)
+	assert.Equal(t, s, b.PersistentState())
+}
+
+func TestMemoryPersistence(t *testing.T) {
+	p := NewMemoryPersistence()
+
+	attrs, err := p.Load("id")
+	assert.NoError(t, err)
+	assert.Empty(t, attrs)
+
+	in := map[string]interface{}{"foo": "bar"}
+	err = p.Save("id", in)
+	assert.NoError(t, err)
+
+	// stored attributes are copies
+	in["foo"] = "baz"
+	attrs, err = p.Load("id")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", attrs["foo"])
+
+	err = p.Save("id", nil)
+	assert.NoError(t, err)
+	attrs, err = p.Load("id")
+	assert.NoError(t, err)
+	assert.Empty(t, attrs)
+}
+
+func TestFilePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attributes.json")
+
+	p, err := NewFilePersistence(path)
+	assert.NoError(t, err)
+
+	err = p.Save("id", map[string]interface{}{"foo": "bar"})
+	assert.NoError(t, err)
+
+	// reopen
+	p, err = NewFilePersistence(path)
+	assert.NoError(t, err)
+	attrs, err := p.Load("id")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", attrs["foo"])
+
+	// invalid file
+	_, err = NewFilePersistence(t.TempDir())
+	assert.Error(t, err)
+}
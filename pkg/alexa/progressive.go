@@ -0,0 +1,75 @@
+package alexa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DirectiveTypeVoicePlayerSpeak is the directive of a progressive response.
+const DirectiveTypeVoicePlayerSpeak DirectiveType = "VoicePlayer.Speak"
+
+// DirectiveError is returned if the directive service rejects a directive.
+type DirectiveError struct {
+	StatusCode int
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+// Error returns a string representation of the error.
+func (e *DirectiveError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("directive service: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("directive service: status %d: %s %s", e.StatusCode, e.Code, e.Message)
+}
+
+// ProgressiveResponder sends progressive responses while the response is built.
+type ProgressiveResponder interface {
+	Speak(ctx context.Context, r *RequestEnvelope, speech string) error
+}
+
+// ProgressiveClient sends progressive responses to the directive service of the request.
+// https://developer.amazon.com/en-US/docs/alexa/custom-skills/send-the-user-a-progressive-response.html
+type ProgressiveClient struct {
+	Client *http.Client
+}
+
+// NewProgressiveClient returns a ProgressiveClient.
+func NewProgressiveClient() *ProgressiveClient {
+	return &ProgressiveClient{Client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+type progressiveRequest struct {
+	Header struct {
+		RequestID string `json:"requestId"`
+	} `json:"header"`
+	Directive struct {
+		Type   DirectiveType `json:"type"`
+		Speech string        `json:"speech"`
+	} `json:"directive"`
+}
+
+// Speak speaks the plain text or SSML speech while the response to the request is built,
+// e.g. "Let me check..." before a slow lookup.
+//
+// Alexa accepts up to five progressive responses per request, rejected ones return a *DirectiveError.
+func (c *ProgressiveClient) Speak(ctx context.Context, r *RequestEnvelope, speech string) error {
+	if r.Request == nil {
+		return ErrNoAPIAccess
+	}
+
+	var pr progressiveRequest
+	pr.Header.RequestID = r.Request.RequestID
+	pr.Directive.Type = DirectiveTypeVoicePlayerSpeak
+	pr.Directive.Speech = speech
+	err := callAPI(ctx, c.Client, r, http.MethodPost, "/v1/directives", pr, nil)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return &DirectiveError{StatusCode: apiErr.StatusCode, Code: apiErr.Code, Message: apiErr.Message}
+	}
+	return err
+}
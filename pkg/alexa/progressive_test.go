@@ -0,0 +1,56 @@
+package alexa_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/alexatest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressiveClient_Speak(t *testing.T) {
+	srv := alexatest.NewDirectiveServer()
+	defer srv.Close()
+	c := alexa.NewProgressiveClient()
+	r := srv.WithAPI(&alexa.RequestEnvelope{Request: &alexa.Request{RequestID: "request-id"}})
+
+	err := c.Speak(context.Background(), r, "<speak>Let me check...</speak>")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []alexatest.Directive{{
+		RequestID: "request-id",
+		Type:      alexa.DirectiveTypeVoicePlayerSpeak,
+		Speech:    "<speak>Let me check...</speak>",
+	}}, srv.Directives())
+}
+
+func TestProgressiveClient_SpeakErrors(t *testing.T) {
+	srv := alexatest.NewDirectiveServer()
+	defer srv.Close()
+	c := alexa.NewProgressiveClient()
+	ctx := context.Background()
+
+	err := c.Speak(ctx, &alexa.RequestEnvelope{Request: &alexa.Request{}}, "Hi")
+	assert.True(t, errors.Is(err, alexa.ErrNoAPIAccess))
+
+	r := srv.WithAPI(&alexa.RequestEnvelope{Request: &alexa.Request{RequestID: "request-id"}})
+	r.Context.System.APIAccessToken = "invalid"
+	err = c.Speak(ctx, r, "Hi")
+	var derr *alexa.DirectiveError
+	assert.True(t, errors.As(err, &derr))
+	assert.Equal(t, http.StatusUnauthorized, derr.StatusCode)
+	assert.Equal(t, "INVALID_AUTHENTICATION_TOKEN", derr.Code)
+
+	r = srv.WithAPI(r)
+	srv.SetStatus(http.StatusInternalServerError)
+	err = c.Speak(ctx, r, "Hi")
+	assert.EqualError(t, err, "directive service: status 500: INTERNAL_SERVICE_EXCEPTION failed")
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = c.Speak(cctx, r, "Hi")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -1,8 +1,10 @@
 package alexa
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // NotFoundError defines a generic not found error.
@@ -131,11 +133,12 @@ func (r *RequestEnvelope) IsIntentConfirmed() bool {
 
 // Slot is an Alexa skill slot.
 type Slot struct {
-	Name        string       `json:"name"`
-	Value       string       `json:"value"`
-	Resolutions *Resolutions `json:"resolutions"`
-	Source      string       `json:"source"`
-	SlotValue   *SlotValue   `json:"slotValue"`
+	Name               string             `json:"name"`
+	Value              string             `json:"value"`
+	ConfirmationStatus ConfirmationStatus `json:"confirmationStatus,omitempty"`
+	Resolutions        *Resolutions       `json:"resolutions,omitempty"`
+	Source             string             `json:"source,omitempty"`
+	SlotValue          *SlotValue         `json:"slotValue,omitempty"`
 }
 
 // SlotValue defines the value or values captured by the slot.
@@ -184,6 +187,40 @@ func (r *RequestEnvelope) SlotValue(name string) string {
 	return s.Value
 }
 
+// ResolvedSlotValue returns the value of the slot if it resolved with ER_SUCCESS_MATCH or an empty string.
+//
+// The value is what the user said, see CanonicalSlotValue for the value it resolved to.
+func (r *RequestEnvelope) ResolvedSlotValue(name string) string {
+	s, err := r.Slot(name)
+	if err != nil {
+		return ""
+	}
+
+	if _, err := s.FirstAuthorityWithMatch(); err != nil {
+		return ""
+	}
+	return s.Value
+}
+
+// CanonicalSlotValue returns the name of the value the slot resolved to with ER_SUCCESS_MATCH or an empty string.
+//
+// Without values in the matching authority, the slot value is returned.
+func (r *RequestEnvelope) CanonicalSlotValue(name string) string {
+	s, err := r.Slot(name)
+	if err != nil {
+		return ""
+	}
+
+	a, err := s.FirstAuthorityWithMatch()
+	if err != nil {
+		return ""
+	}
+	if len(a.Values) == 0 || a.Values[0].Value == nil || a.Values[0].Value.Name == "" {
+		return s.Value
+	}
+	return a.Values[0].Value.Name
+}
+
 // SlotResolutionsPerAuthority returns the list of ResolutionsPerAuthority.
 func (s *Slot) SlotResolutionsPerAuthority() ([]*PerAuthority, error) {
 	if s.Resolutions == nil {
@@ -266,8 +303,53 @@ const (
 	TypeSessionEndedRequest RequestType = "SessionEndedRequest"
 	// TypeCanFulfillIntentRequest defines a can fulfill intent request type.
 	TypeCanFulfillIntentRequest RequestType = "CanFulfillIntentRequest"
+	// TypeSystemExceptionEncountered is sent when a directive of the response failed.
+	TypeSystemExceptionEncountered RequestType = "System.ExceptionEncountered"
+
+	// TypeAudioPlayerPlaybackStarted is sent when the audio player starts playing a stream.
+	TypeAudioPlayerPlaybackStarted RequestType = "AudioPlayer.PlaybackStarted"
+	// TypeAudioPlayerPlaybackFinished is sent when a stream finished playing.
+	TypeAudioPlayerPlaybackFinished RequestType = "AudioPlayer.PlaybackFinished"
+	// TypeAudioPlayerPlaybackStopped is sent when the user or a Stop directive stopped the stream.
+	TypeAudioPlayerPlaybackStopped RequestType = "AudioPlayer.PlaybackStopped"
+	// TypeAudioPlayerPlaybackNearlyFinished is sent when the next stream can be enqueued.
+	TypeAudioPlayerPlaybackNearlyFinished RequestType = "AudioPlayer.PlaybackNearlyFinished"
+	// TypeAudioPlayerPlaybackFailed is sent when a stream could not be played.
+	TypeAudioPlayerPlaybackFailed RequestType = "AudioPlayer.PlaybackFailed"
+
+	// TypePlaybackControllerNextCommandIssued is sent when the user pressed the next button.
+	TypePlaybackControllerNextCommandIssued RequestType = "PlaybackController.NextCommandIssued"
+	// TypePlaybackControllerPreviousCommandIssued is sent when the user pressed the previous button.
+	TypePlaybackControllerPreviousCommandIssued RequestType = "PlaybackController.PreviousCommandIssued"
+	// TypePlaybackControllerPlayCommandIssued is sent when the user pressed the play button.
+	TypePlaybackControllerPlayCommandIssued RequestType = "PlaybackController.PlayCommandIssued"
+	// TypePlaybackControllerPauseCommandIssued is sent when the user pressed the pause button.
+	TypePlaybackControllerPauseCommandIssued RequestType = "PlaybackController.PauseCommandIssued"
 )
 
+// AudioPlayerRequestTypes are the request types of the audio player.
+var AudioPlayerRequestTypes = []RequestType{
+	TypeAudioPlayerPlaybackStarted,
+	TypeAudioPlayerPlaybackFinished,
+	TypeAudioPlayerPlaybackStopped,
+	TypeAudioPlayerPlaybackNearlyFinished,
+	TypeAudioPlayerPlaybackFailed,
+}
+
+// PlaybackControllerRequestTypes are the request types of the buttons of a device.
+var PlaybackControllerRequestTypes = []RequestType{
+	TypePlaybackControllerNextCommandIssued,
+	TypePlaybackControllerPreviousCommandIssued,
+	TypePlaybackControllerPlayCommandIssued,
+	TypePlaybackControllerPauseCommandIssued,
+}
+
+// IsPlayback returns true for AudioPlayer and PlaybackController requests,
+// the response must not contain speech, a card or a reprompt.
+func (t RequestType) IsPlayback() bool {
+	return strings.HasPrefix(string(t), "AudioPlayer.") || strings.HasPrefix(string(t), "PlaybackController.")
+}
+
 // RequestType returns the type of the request.
 func (r *RequestEnvelope) RequestType() RequestType {
 	if r.Request == nil {
@@ -324,11 +406,23 @@ type Request struct {
 	Intent      Intent          `json:"intent,omitempty"`
 	Reason      string          `json:"reason,omitempty"`
 	DialogState DialogStateType `json:"dialogState,omitempty"`
+	// Token and OffsetInMilliseconds are the stream of AudioPlayer requests.
+	Token                string        `json:"token,omitempty"`
+	OffsetInMilliseconds int           `json:"offsetInMilliseconds,omitempty"`
+	Error                *RequestError `json:"error,omitempty"`
+	// CurrentPlaybackState is the state of the audio player when a stream failed.
+	CurrentPlaybackState *ContextAudioPlayer `json:"currentPlaybackState,omitempty"`
 
 	Context *Context `json:"-"`
 	Session *Session `json:"-"`
 }
 
+// RequestError is the error of a failed stream, a failed directive or an ended session.
+type RequestError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
 // ContextUser a string that represents a unique identifier for the Amazon account for which the skill is enabled.
 type ContextUser struct {
 	UserID      string `json:"userId"`
@@ -346,21 +440,16 @@ type ContextApplication struct {
 //
 // Use it to verify the request is meant for your Skill.
 func (r *RequestEnvelope) ApplicationID() (string, error) {
-	// System first, then Session
-	if r.Session == nil {
-		s, err := r.System()
-		if err != nil || s.Application == nil {
-			return "", &NotFoundError{"Context.System.Application", ""}
-		}
-
-		return s.Application.ApplicationID, nil
+	if r.Session != nil && r.Session.Application != nil {
+		return r.Session.Application.ApplicationID, nil
 	}
 
-	if r.Session.Application == nil {
-		return "", &NotFoundError{"Session.Application", ""}
+	s, err := r.System()
+	if err != nil || s.Application == nil {
+		return "", &NotFoundError{"Application", ""}
 	}
 
-	return r.Session.Application.ApplicationID, nil
+	return s.Application.ApplicationID, nil
 }
 
 // Session represents the Alexa skill session.
@@ -552,4 +641,22 @@ type RequestEnvelope struct {
 	Session *Session `json:"session"`
 	Context *Context `json:"context"`
 	Request *Request `json:"request"`
+
+	ctx context.Context
+}
+
+// InvocationContext returns the context of the invocation, e.g. to cancel calls to Alexa services
+// when the Lambda invocation or HTTP request is cancelled. It is never nil.
+func (r *RequestEnvelope) InvocationContext() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// WithInvocationContext returns a shallow copy of the request with the invocation context.
+func (r *RequestEnvelope) WithInvocationContext(ctx context.Context) *RequestEnvelope {
+	r2 := *r
+	r2.ctx = ctx
+	return &r2
 }
@@ -158,9 +158,16 @@ func TestApplicationID(t *testing.T) {
 
 	assert.Equal(t, "foo", ID)
 
+	// falls back to system without session application
 	r.Session = &Session{}
 	ID, err = r.ApplicationID()
 
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", ID)
+
+	r.Context = nil
+	ID, err = r.ApplicationID()
+
 	assert.Error(t, err)
 	assert.Equal(t, "", ID)
 
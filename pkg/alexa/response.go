@@ -1,22 +1,53 @@
 package alexa
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/ssml"
 )
 
 // Stream represents a response directive audio item stream.
+//
+// ExpectedPreviousToken is required to enqueue a stream, it must be the token of the stream playing.
 type Stream struct {
-	Token                string `json:"token,omitempty"`
-	URL                  string `json:"url,omitempty"`
-	OffsetInMilliseconds int    `json:"offsetInMilliseconds,omitempty"`
+	Token                 string `json:"token,omitempty"`
+	ExpectedPreviousToken string `json:"expectedPreviousToken,omitempty"`
+	URL                   string `json:"url,omitempty"`
+	OffsetInMilliseconds  int    `json:"offsetInMilliseconds,omitempty"`
+}
+
+// AudioItemMetadata is shown on devices with a screen while the stream is playing.
+type AudioItemMetadata struct {
+	Title    string `json:"title,omitempty"`
+	Subtitle string `json:"subtitle,omitempty"`
 }
 
 // AudioItem represents a response directive audio item.
 type AudioItem struct {
-	Stream Stream `json:"stream,omitempty"`
+	Stream   Stream             `json:"stream,omitempty"`
+	Metadata *AudioItemMetadata `json:"metadata,omitempty"`
 }
 
+// Play behaviors of the AudioPlayer.Play directive.
+const (
+	// PlayBehaviorReplaceAll plays the stream immediately and clears the queue.
+	PlayBehaviorReplaceAll = "REPLACE_ALL"
+	// PlayBehaviorEnqueue adds the stream to the end of the queue.
+	PlayBehaviorEnqueue = "ENQUEUE"
+	// PlayBehaviorReplaceEnqueued replaces the queue, the current stream keeps playing.
+	PlayBehaviorReplaceEnqueued = "REPLACE_ENQUEUED"
+)
+
+// Clear behaviors of the AudioPlayer.ClearQueue directive.
+const (
+	// ClearBehaviorClearEnqueued clears the queue, the current stream keeps playing.
+	ClearBehaviorClearEnqueued = "CLEAR_ENQUEUED"
+	// ClearBehaviorClearAll clears the queue and stops the current stream.
+	ClearBehaviorClearAll = "CLEAR_ALL"
+)
+
 // DirectiveType represents various Directive Types.
 type DirectiveType string
 
@@ -26,15 +57,27 @@ const (
 	DirectiveTypeDialogElicitSlot    DirectiveType = "Dialog.ElicitSlot"
 	DirectiveTypeDialogConfirmSlot   DirectiveType = "Dialog.ConfirmSlot"
 	DirectiveTypeDialogConfirmIntent DirectiveType = "Dialog.ConfirmIntent"
+	DirectiveTypeAPLRenderDocument   DirectiveType = "Alexa.Presentation.APL.RenderDocument"
+	DirectiveTypeAPLExecuteCommands  DirectiveType = "Alexa.Presentation.APL.ExecuteCommands"
+	DirectiveTypeAudioPlayerPlay     DirectiveType = "AudioPlayer.Play"
+	DirectiveTypeAudioPlayerStop     DirectiveType = "AudioPlayer.Stop"
+	DirectiveTypeAudioPlayerClear    DirectiveType = "AudioPlayer.ClearQueue"
 )
 
 // Directive represents a response directive.
 type Directive struct {
 	Type          DirectiveType `json:"type,omitempty"`
 	SlotToElicit  string        `json:"slotToElicit,omitempty"`
+	SlotToConfirm string        `json:"slotToConfirm,omitempty"`
 	UpdatedIntent *Intent       `json:"updatedIntent,omitempty"`
 	PlayBehavior  string        `json:"playBehavior,omitempty"`
 	AudioItem     *AudioItem    `json:"audioItem,omitempty"`
+	ClearBehavior string        `json:"clearBehavior,omitempty"`
+	// Token identifies the APL document, ExecuteCommands must use the token of the rendered document.
+	Token       string                 `json:"token,omitempty"`
+	Document    *APLDocument           `json:"document,omitempty"`
+	Datasources map[string]interface{} `json:"datasources,omitempty"`
+	Commands    []APLCommand           `json:"commands,omitempty"`
 }
 
 // OutputSpeech represents a speech response.
@@ -52,6 +95,8 @@ type Card struct {
 	Text    string `json:"text,omitempty"`
 	Content string `json:"content,omitempty"`
 	Image   *Image `json:"image,omitempty"`
+	// Permissions are requested with an AskForPermissionsConsent card.
+	Permissions []string `json:"permissions,omitempty"`
 }
 
 // Image represents a card image.
@@ -104,17 +149,47 @@ type ResponseBuilder struct {
 	shouldEndSession bool
 	sessionAttr      map[string]interface{}
 	canFulfillIntent *CanFulfillIntent
+	persistent       *PersistentState
+	budget           *ssml.Budget
+	truncate         bool
+	device           DeviceClass
+	apl              bool
+	requestType      RequestType
+	policy           ResponsePolicy
+	rejected         error
 }
 
 // With applies an Response.
+//
+// The response policy decides if the title, text and image are presented as card or APL document,
+// or not at all, for the device of the request, see WithRequest.
+// If the response has no text, the card text is the plain text of the speech.
 func (b *ResponseBuilder) With(resp Response) {
-	if resp.Image != "" {
-		b.WithStandardCard(resp.Title, resp.Text, &Image{
-			SmallImageURL: fmt.Sprintf(resp.Image, "small"),
-			LargeImageURL: fmt.Sprintf(resp.Image, "large"),
-		})
+	text := resp.Text
+	if text == "" {
+		text = plainText(resp.Speech)
+	}
+
+	policy := b.policy
+	if policy == nil {
+		policy = DefaultResponsePolicy
+	}
+	mode, size := policy(b.device)
+	if mode == OutputAPL && !b.apl {
+		mode = OutputCard
+	}
+
+	switch mode {
+	case OutputAPL:
+		doc, datasources := responseDocument(resp, text, size).Build()
+		b.WithAPLRenderDocument("response", doc, datasources)
+	case OutputCard:
+		if resp.Image != nil {
+			b.WithStandardCard(resp.Title, text, resp.Image)
+		} else {
+			b.WithSimpleCard(resp.Title, text)
+		}
 	}
-	b.WithSimpleCard(resp.Title, resp.Text)
 	if resp.Speech != "" {
 		if resp.Reprompt {
 			b.WithReprompt(resp.Speech)
@@ -125,12 +200,27 @@ func (b *ResponseBuilder) With(resp Response) {
 	b.WithShouldEndSession(resp.End)
 }
 
+// WithRequest adapts the responses applied With to the device of the request,
+// and omits speech, cards and reprompts from responses to playback requests.
+func (b *ResponseBuilder) WithRequest(r *RequestEnvelope) *ResponseBuilder {
+	b.device = r.DeviceClass()
+	b.apl = r.SupportsAPL()
+	b.requestType = r.RequestType()
+	return b
+}
+
+// WithResponsePolicy sets the policy used With, DefaultResponsePolicy if not set.
+func (b *ResponseBuilder) WithResponsePolicy(policy ResponsePolicy) *ResponseBuilder {
+	b.policy = policy
+	return b
+}
+
 // WithSpeech sets the output speech on the response.
 //
 // If the text contains SSML speak tags, it will be set as SSML speech,
 // otherwise it will be set as plain text speech.
 func (b *ResponseBuilder) WithSpeech(text string) *ResponseBuilder {
-	if strings.HasPrefix(text, "<speak>") && strings.HasSuffix(text, "</speak>") {
+	if isSSML(text) {
 		b.speech = &OutputSpeech{
 			Type: "SSML",
 			SSML: text,
@@ -145,9 +235,25 @@ func (b *ResponseBuilder) WithSpeech(text string) *ResponseBuilder {
 	return b
 }
 
+// plainText returns the text of SSML speech, plain text speech as is and nothing for invalid SSML.
+func plainText(speech string) string {
+	if !isSSML(speech) {
+		return speech
+	}
+	text, err := ssml.PlainText(speech)
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+func isSSML(text string) bool {
+	return strings.HasPrefix(text, "<speak>") && strings.HasSuffix(text, "</speak>")
+}
+
 // WithReprompt sets the reprompt output speech on the response.
 func (b *ResponseBuilder) WithReprompt(text string) *ResponseBuilder {
-	if strings.HasPrefix(text, "<speak>") && strings.HasSuffix(text, "</speak>") {
+	if isSSML(text) {
 		b.reprompt = &OutputSpeech{
 			Type: "SSML",
 			SSML: text,
@@ -191,6 +297,19 @@ func (b *ResponseBuilder) WithShouldEndSession(end bool) *ResponseBuilder {
 	return b
 }
 
+// Reject rejects the request with the given error, no response is sent.
+//
+// The error wraps ErrRejected, the server returns it instead of the response.
+func (b *ResponseBuilder) Reject(err error) *ResponseBuilder {
+	b.rejected = fmt.Errorf("%w: %v", ErrRejected, err)
+	return b
+}
+
+// Rejected returns the error the request was rejected with, or nil.
+func (b *ResponseBuilder) Rejected() error {
+	return b.rejected
+}
+
 // WithSessionAttributes sets the session attributes on the response.
 func (b *ResponseBuilder) WithSessionAttributes(attr map[string]interface{}) *ResponseBuilder {
 	b.sessionAttr = attr
@@ -211,9 +330,170 @@ func (b *ResponseBuilder) AddDirective(directive *Directive) *ResponseBuilder {
 	return b
 }
 
+// WithDialogDelegate delegates the next dialog step to Alexa.
+//
+// The intent is optional, use it to change slot values or the intent.
+// Speech and reprompt are omitted from the response, as Alexa does not allow them with Dialog.Delegate.
+func (b *ResponseBuilder) WithDialogDelegate(intent *Intent) *ResponseBuilder {
+	return b.AddDirective(&Directive{
+		Type:          DirectiveTypeDialogDelegate,
+		UpdatedIntent: intent,
+	})
+}
+
+// WithDialogElicitSlot asks the user for the value of the slot, the speech must contain the question.
+//
+// The intent is optional, use it to change slot values.
+func (b *ResponseBuilder) WithDialogElicitSlot(slot string, intent *Intent) *ResponseBuilder {
+	return b.AddDirective(&Directive{
+		Type:          DirectiveTypeDialogElicitSlot,
+		SlotToElicit:  slot,
+		UpdatedIntent: intent,
+	})
+}
+
+// WithDialogConfirmSlot asks the user to confirm the value of the slot, the speech must contain the question.
+//
+// The intent is optional, use it to change slot values.
+func (b *ResponseBuilder) WithDialogConfirmSlot(slot string, intent *Intent) *ResponseBuilder {
+	return b.AddDirective(&Directive{
+		Type:          DirectiveTypeDialogConfirmSlot,
+		SlotToConfirm: slot,
+		UpdatedIntent: intent,
+	})
+}
+
+// WithDialogConfirmIntent asks the user to confirm the intent, the speech must contain the question.
+//
+// The intent is optional, use it to change slot values.
+func (b *ResponseBuilder) WithDialogConfirmIntent(intent *Intent) *ResponseBuilder {
+	return b.AddDirective(&Directive{
+		Type:          DirectiveTypeDialogConfirmIntent,
+		UpdatedIntent: intent,
+	})
+}
+
+// WithAPLRenderDocument renders the APL document with the datasources, see WithAPL.
+func (b *ResponseBuilder) WithAPLRenderDocument(
+	token string, doc *APLDocument, datasources map[string]interface{},
+) *ResponseBuilder {
+	return b.AddDirective(&Directive{
+		Type:        DirectiveTypeAPLRenderDocument,
+		Token:       token,
+		Document:    doc,
+		Datasources: datasources,
+	})
+}
+
+// WithAPLExecuteCommands runs the commands on the APL document rendered with the token.
+func (b *ResponseBuilder) WithAPLExecuteCommands(token string, commands ...APLCommand) *ResponseBuilder {
+	return b.AddDirective(&Directive{
+		Type:     DirectiveTypeAPLExecuteCommands,
+		Token:    token,
+		Commands: commands,
+	})
+}
+
+// WithAudioPlayerPlay plays or enqueues the stream, see the PlayBehavior constants.
+//
+// The metadata is optional.
+func (b *ResponseBuilder) WithAudioPlayerPlay(
+	behavior string, stream Stream, metadata *AudioItemMetadata,
+) *ResponseBuilder {
+	return b.AddDirective(&Directive{
+		Type:         DirectiveTypeAudioPlayerPlay,
+		PlayBehavior: behavior,
+		AudioItem:    &AudioItem{Stream: stream, Metadata: metadata},
+	})
+}
+
+// WithAudioPlayerStop stops the current stream.
+func (b *ResponseBuilder) WithAudioPlayerStop() *ResponseBuilder {
+	return b.AddDirective(&Directive{Type: DirectiveTypeAudioPlayerStop})
+}
+
+// WithAudioPlayerClearQueue clears the queue, see the ClearBehavior constants.
+func (b *ResponseBuilder) WithAudioPlayerClearQueue(behavior string) *ResponseBuilder {
+	return b.AddDirective(&Directive{
+		Type:          DirectiveTypeAudioPlayerClear,
+		ClearBehavior: behavior,
+	})
+}
+
+// dialogDirectiveTypes are the directives continuing the dialog with Alexa.
+var dialogDirectiveTypes = []DirectiveType{
+	DirectiveTypeDialogDelegate,
+	DirectiveTypeDialogElicitSlot,
+	DirectiveTypeDialogConfirmSlot,
+	DirectiveTypeDialogConfirmIntent,
+}
+
+// hasDirective returns true if a directive of one of the types was added.
+func (b *ResponseBuilder) hasDirective(types ...DirectiveType) bool {
+	for _, d := range b.directives {
+		for _, t := range types {
+			if d.Type == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WithSpeechBudget sets the budget to measure the speech and reprompt against, see ApplyBudget.
+func (b *ResponseBuilder) WithSpeechBudget(budget *ssml.Budget, truncate bool) *ResponseBuilder {
+	b.budget = budget
+	b.truncate = truncate
+	return b
+}
+
+// ApplyBudget measures the speech and reprompt against the speech budget, if set.
+//
+// Speech over budget is truncated at sentence boundaries if set to, otherwise a *ssml.BudgetError is returned.
+// It returns true if any speech was truncated.
+func (b *ResponseBuilder) ApplyBudget() (bool, error) {
+	if b.budget == nil {
+		return false, nil
+	}
+
+	var truncated bool
+	for _, s := range []*OutputSpeech{b.speech, b.reprompt} {
+		if s == nil {
+			continue
+		}
+		t, err := b.applyBudget(s)
+		if err != nil {
+			return truncated, err
+		}
+		truncated = truncated || t
+	}
+	return truncated, nil
+}
+
+func (b *ResponseBuilder) applyBudget(s *OutputSpeech) (bool, error) {
+	if s.Type != "SSML" {
+		err := b.budget.CheckText(s.Text)
+		if err == nil || !b.truncate {
+			return false, err
+		}
+		s.Text = b.budget.TruncateText(s.Text)
+		return true, nil
+	}
+
+	err := b.budget.Check(s.SSML)
+	if err == nil || !b.truncate || !errors.Is(err, ssml.ErrBudget) {
+		return false, err
+	}
+	if s.SSML, err = b.budget.Truncate(s.SSML); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Build builds the response from the given information.
+//
+// The speech budget is not applied, call ApplyBudget before.
 func (b *ResponseBuilder) Build() *ResponseEnvelope {
-	// TODO: empty response with directive(s), like Dialog:Delegate
 	r := &ResponseEnvelope{
 		Version:           "1.0",
 		SessionAttributes: b.sessionAttr,
@@ -232,5 +512,20 @@ func (b *ResponseBuilder) Build() *ResponseEnvelope {
 	if b.canFulfillIntent != nil {
 		r.Response.CanFulfillIntent = b.canFulfillIntent
 	}
+
+	// the dialog continues with Alexa, the session must stay open
+	if b.hasDirective(dialogDirectiveTypes...) {
+		r.Response.ShouldEndSession = false
+	}
+	if b.hasDirective(DirectiveTypeDialogDelegate) {
+		r.Response.OutputSpeech = nil
+		r.Response.Reprompt = nil
+	}
+	// Alexa rejects speech, cards and reprompts in responses to playback requests
+	if b.requestType.IsPlayback() {
+		r.Response.OutputSpeech = nil
+		r.Response.Card = nil
+		r.Response.Reprompt = nil
+	}
 	return r
 }
@@ -1,10 +1,13 @@
 package alexa
 
 import (
-	"github.com/drpsychick/go-alexa-lambda/ssml"
-	"github.com/stretchr/testify/assert"
+	"errors"
 	"strings"
 	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/ssml"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestWith_Functions(t *testing.T) {
@@ -117,12 +120,14 @@ func TestResponseBuilder_With(t *testing.T) {
 				if strings.HasPrefix(tt.args.resp.Speech, `<speak>`) {
 					if tt.args.resp.Reprompt {
 						assert.Equal(t, tt.args.resp.Speech, b.reprompt.SSML)
+						assert.Nil(t, b.speech)
 					} else {
 						assert.Equal(t, tt.args.resp.Speech, b.speech.SSML)
 					}
 				} else {
 					if tt.args.resp.Reprompt {
 						assert.Equal(t, tt.args.resp.Speech, b.reprompt.Text)
+						assert.Nil(t, b.speech)
 					} else {
 						assert.Equal(t, tt.args.resp.Speech, b.speech.Text)
 					}
@@ -131,3 +136,113 @@ func TestResponseBuilder_With(t *testing.T) {
 		})
 	}
 }
+
+func TestResponseBuilder_WithCardFromSpeech(t *testing.T) {
+	b := &ResponseBuilder{}
+	b.With(Response{Title: "title", Speech: `<speak>Hello<break time="1s"/><sub alias="World Wide Web">WWW</sub></speak>`})
+	assert.Equal(t, "Hello World Wide Web", b.card.Content)
+
+	b.With(Response{Title: "title", Speech: `<speak><audio src="soundbank://a"/><p>One.</p><p><s>Two.</s><s>Three.</s></p></speak>`})
+	assert.Equal(t, "One.\nTwo. Three.", b.card.Content)
+
+	b.With(Response{Title: "title", Speech: "hello"})
+	assert.Equal(t, "hello", b.card.Content)
+
+	b.With(Response{Title: "title", Speech: "<speak><foo/></speak>"})
+	assert.Equal(t, "", b.card.Content)
+}
+
+func TestResponseBuilder_ApplyBudget(t *testing.T) {
+	long := strings.Repeat("Hello there. ", 1000)
+
+	b := &ResponseBuilder{}
+	b.WithSpeech("<speak>" + long + "</speak>").WithReprompt(long)
+	truncated, err := b.ApplyBudget()
+	assert.NoError(t, err)
+	assert.False(t, truncated)
+
+	b.WithSpeechBudget(ssml.NewBudget(), false)
+	_, err = b.ApplyBudget()
+	var budgetErr *ssml.BudgetError
+	assert.True(t, errors.As(err, &budgetErr))
+
+	b.WithSpeechBudget(ssml.NewBudget(), true)
+	res := b.Build()
+	assert.Len(t, res.Response.OutputSpeech.SSML, len(long)+len("<speak></speak>"))
+
+	truncated, err = b.ApplyBudget()
+	assert.NoError(t, err)
+	assert.True(t, truncated)
+	res = b.Build()
+	assert.LessOrEqual(t, len(res.Response.OutputSpeech.SSML), ssml.MaxChars)
+	assert.True(t, strings.HasSuffix(res.Response.OutputSpeech.SSML, "Hello there. </speak>"))
+	assert.Len(t, res.Response.Reprompt.OutputSpeech.Text, 615*13-1)
+}
+
+func TestResponseBuilder_AudioPlayerDirectives(t *testing.T) {
+	b := &ResponseBuilder{}
+	b.WithAudioPlayerPlay(PlayBehaviorReplaceAll, Stream{
+		Token:                "token",
+		URL:                  "https://example.com/a.mp3",
+		OffsetInMilliseconds: 1000,
+	}, &AudioItemMetadata{Title: "title"}).
+		WithAudioPlayerClearQueue(ClearBehaviorClearEnqueued).
+		WithAudioPlayerStop().
+		WithSpeech("Playing")
+
+	res := b.Build()
+
+	assert.Equal(t, "Playing", res.Response.OutputSpeech.Text)
+	json, err := jsoniter.MarshalToString(res.Response.Directives)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"type":"AudioPlayer.Play","playBehavior":"REPLACE_ALL","audioItem":{`+
+		`"stream":{"token":"token","url":"https://example.com/a.mp3","offsetInMilliseconds":1000},`+
+		`"metadata":{"title":"title"}}},`+
+		`{"type":"AudioPlayer.ClearQueue","clearBehavior":"CLEAR_ENQUEUED"},`+
+		`{"type":"AudioPlayer.Stop"}]`, json)
+}
+
+func TestResponseBuilder_DialogDirectives(t *testing.T) {
+	intent := &Intent{
+		Name:               "Intent",
+		ConfirmationStatus: ConfirmationStatusNone,
+		Slots: map[string]*Slot{
+			"Slot": {Name: "Slot", Value: "value"},
+		},
+	}
+
+	b := &ResponseBuilder{}
+	b.WithSpeech("speech").WithReprompt("reprompt").WithShouldEndSession(true)
+	b.WithDialogDelegate(intent)
+	res := b.Build()
+
+	assert.Equal(t, DirectiveTypeDialogDelegate, res.Response.Directives[0].Type)
+	assert.Equal(t, intent, res.Response.Directives[0].UpdatedIntent)
+	assert.Nil(t, res.Response.OutputSpeech)
+	assert.Nil(t, res.Response.Reprompt)
+	assert.False(t, res.Response.ShouldEndSession)
+
+	b = &ResponseBuilder{}
+	b.WithSpeech("which slot?").WithShouldEndSession(true)
+	b.WithDialogElicitSlot("Slot", nil)
+	res = b.Build()
+
+	assert.Equal(t, DirectiveTypeDialogElicitSlot, res.Response.Directives[0].Type)
+	assert.Equal(t, "Slot", res.Response.Directives[0].SlotToElicit)
+	assert.Equal(t, "which slot?", res.Response.OutputSpeech.Text)
+	assert.False(t, res.Response.ShouldEndSession)
+
+	b = &ResponseBuilder{}
+	b.WithDialogConfirmSlot("Slot", intent)
+	res = b.Build()
+
+	assert.Equal(t, DirectiveTypeDialogConfirmSlot, res.Response.Directives[0].Type)
+	assert.Equal(t, "Slot", res.Response.Directives[0].SlotToConfirm)
+
+	b = &ResponseBuilder{}
+	b.WithDialogConfirmIntent(intent)
+	res = b.Build()
+
+	assert.Equal(t, DirectiveTypeDialogConfirmIntent, res.Response.Directives[0].Type)
+	assert.Equal(t, intent, res.Response.Directives[0].UpdatedIntent)
+}
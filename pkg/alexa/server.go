@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"sync"
 
 	"github.com/aws/aws-lambda-go/lambda"
@@ -24,25 +27,67 @@ func (fn HandlerFunc) Serve(b *ResponseBuilder, r *RequestEnvelope) {
 	fn(b, r)
 }
 
+// ErrRejected is returned by the server for requests rejected by the handler, see ResponseBuilder.Reject.
+var ErrRejected = errors.New("alexa: request rejected")
+
 // A Server defines parameters for running an Alexa server.
 type Server struct {
 	Handler Handler
 }
 
 // Invoke calls the handler, and serializes the response.
+//
+// A request rejected by the handler returns an error wrapping ErrRejected.
+//
+// The context is passed to the handler, see RequestEnvelope.InvocationContext.
 func (s *Server) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
 	req := &RequestEnvelope{}
 	if err := jsoniter.Unmarshal(payload, req); err != nil {
 		return nil, err
 	}
+	req.ctx = ctx
 
 	builder := &ResponseBuilder{}
 	s.Handler.Serve(builder, req)
+	if err := builder.Rejected(); err != nil {
+		return nil, err
+	}
 
 	// Idea: BuildJson -> then the `build()` can be private
 	return jsoniter.Marshal(builder.Build())
 }
 
+// maxRequestSize limits the size of a request body accepted over HTTP.
+const maxRequestSize = 1 << 20
+
+// ServeHTTP serves Alexa requests POSTed over HTTP.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, "could not read request", http.StatusBadRequest)
+		return
+	}
+
+	res, err := s.Invoke(r.Context(), payload)
+	if errors.Is(err, ErrRejected) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	_, _ = w.Write(res)
+}
+
 // Serve serves the handler.
 func (s *Server) Serve() error {
 	// TODO: decide if we want a DefaultServeMux
@@ -61,13 +106,41 @@ func Serve(h Handler) error {
 	return srv.Serve()
 }
 
+// IntentSlot declares a slot the mux resolves before serving the intent handler.
+type IntentSlot struct {
+	// Name is the name of the slot.
+	Name string
+	// Optional slots are not elicited when they have no value.
+	Optional bool
+	// Validate requires the slot value to resolve with ER_SUCCESS_MATCH.
+	Validate bool
+	// Elicit is served instead of the intent handler to prompt for the slot.
+	// The mux adds the Dialog.ElicitSlot directive unless it ends the session or adds a dialog directive.
+	// Without, the dialog is delegated to Alexa, which uses the prompts of the interaction model.
+	Elicit Handler
+}
+
+// isResolved returns true if the slot of the request satisfies the declaration.
+func (s IntentSlot) isResolved(r *RequestEnvelope) bool {
+	slot, err := r.Slot(s.Name)
+	if err != nil || slot.Value == "" {
+		return s.Optional
+	}
+	if !s.Validate {
+		return true
+	}
+
+	_, err = slot.FirstAuthorityWithMatch()
+	return err == nil
+}
+
 // ServeMux is an Alexa request multiplexer.
 type ServeMux struct {
 	mu          sync.RWMutex
 	logger      log.Logger
 	types       map[RequestType]Handler
 	intents     map[string]Handler
-	intentSlots map[string]string
+	intentSlots map[string][]IntentSlot
 }
 
 // NewServerMux creates a new server mux.
@@ -76,7 +149,7 @@ func NewServerMux(log log.Logger) *ServeMux {
 		logger:      log,
 		types:       map[RequestType]Handler{},
 		intents:     map[string]Handler{},
-		intentSlots: map[string]string{},
+		intentSlots: map[string][]IntentSlot{},
 	}
 }
 
@@ -103,6 +176,13 @@ func (m *ServeMux) Handler(r *RequestEnvelope) (Handler, error) {
 		return nil, fmt.Errorf("server: unknown intent %s", r.IntentName())
 	}
 
+	slots := m.intentSlots[r.IntentName()]
+	for _, s := range slots {
+		if !s.isResolved(r) {
+			return elicitHandler(s, slots), nil
+		}
+	}
+
 	return h, nil
 }
 
@@ -131,6 +211,35 @@ func (m *ServeMux) HandleRequestTypeFunc(requestType RequestType, handler Handle
 	m.HandleRequestType(requestType, handler)
 }
 
+// HandleAudioPlayer registers the handler for all AudioPlayer requests, see AudioPlayerRequestTypes.
+//
+// Handlers registered for a single type are replaced.
+func (m *ServeMux) HandleAudioPlayer(handler Handler) {
+	for _, t := range AudioPlayerRequestTypes {
+		m.HandleRequestType(t, handler)
+	}
+}
+
+// HandleAudioPlayerFunc registers the handler function for all AudioPlayer requests.
+func (m *ServeMux) HandleAudioPlayerFunc(handler HandlerFunc) {
+	m.HandleAudioPlayer(handler)
+}
+
+// HandlePlaybackController registers the handler for all PlaybackController requests,
+// see PlaybackControllerRequestTypes.
+//
+// Handlers registered for a single type are replaced.
+func (m *ServeMux) HandlePlaybackController(handler Handler) {
+	for _, t := range PlaybackControllerRequestTypes {
+		m.HandleRequestType(t, handler)
+	}
+}
+
+// HandlePlaybackControllerFunc registers the handler function for all PlaybackController requests.
+func (m *ServeMux) HandlePlaybackControllerFunc(handler HandlerFunc) {
+	m.HandlePlaybackController(handler)
+}
+
 // HandleIntent registers the handler for the given intent.
 func (m *ServeMux) HandleIntent(intent string, handler Handler) {
 	if handler == nil {
@@ -140,6 +249,7 @@ func (m *ServeMux) HandleIntent(intent string, handler Handler) {
 	m.mu.Lock()
 
 	m.intents[intent] = handler
+	delete(m.intentSlots, intent)
 
 	m.mu.Unlock()
 }
@@ -149,6 +259,75 @@ func (m *ServeMux) HandleIntentFunc(intent string, handler HandlerFunc) {
 	m.HandleIntent(intent, handler)
 }
 
+// HandleIntentWithSlots registers the handler for the given intent, which is only served
+// once all declared slots are resolved.
+//
+// Missing or invalid slots are elicited in the order they are declared.
+func (m *ServeMux) HandleIntentWithSlots(intent string, handler Handler, slots ...IntentSlot) {
+	m.HandleIntent(intent, handler)
+
+	m.mu.Lock()
+
+	m.intentSlots[intent] = slots
+
+	m.mu.Unlock()
+}
+
+// HandleIntentWithSlotsFunc registers the handler function for the given intent, which is only served
+// once all declared slots are resolved.
+func (m *ServeMux) HandleIntentWithSlotsFunc(intent string, handler HandlerFunc, slots ...IntentSlot) {
+	m.HandleIntentWithSlots(intent, handler, slots...)
+}
+
+// dialogIntent returns the intent of the request to be passed back to Alexa with a dialog directive.
+//
+// Values of the slots which failed validation are cleared, so Alexa does not consider them confirmed.
+func dialogIntent(r *RequestEnvelope, slots []IntentSlot) *Intent {
+	i := r.Request.Intent
+	intent := &Intent{
+		Name:               i.Name,
+		ConfirmationStatus: i.ConfirmationStatus,
+		Slots:              map[string]*Slot{},
+	}
+	if intent.ConfirmationStatus == "" {
+		intent.ConfirmationStatus = ConfirmationStatusNone
+	}
+
+	invalid := map[string]bool{}
+	for _, s := range slots {
+		invalid[s.Name] = !s.isResolved(r)
+	}
+	for name, s := range i.Slots {
+		status := s.ConfirmationStatus
+		if status == "" {
+			status = ConfirmationStatusNone
+		}
+		value := s.Value
+		if invalid[name] {
+			value, status = "", ConfirmationStatusNone
+		}
+		intent.Slots[name] = &Slot{Name: s.Name, Value: value, ConfirmationStatus: status}
+	}
+	return intent
+}
+
+// elicitHandler returns a handler eliciting the slot, keeping the session attributes.
+func elicitHandler(s IntentSlot, slots []IntentSlot) HandlerFunc {
+	return HandlerFunc(func(b *ResponseBuilder, r *RequestEnvelope) {
+		b.WithSessionState(NewSessionState(r))
+
+		if s.Elicit == nil {
+			b.WithDialogDelegate(dialogIntent(r, slots))
+			return
+		}
+
+		s.Elicit.Serve(b, r)
+		if !b.shouldEndSession && !b.hasDirective(dialogDirectiveTypes...) {
+			b.WithDialogElicitSlot(s.Name, dialogIntent(r, slots))
+		}
+	})
+}
+
 // fallbackHandler returns a fatal error card.
 func fallbackHandler(err error) HandlerFunc {
 	return HandlerFunc(func(b *ResponseBuilder, r *RequestEnvelope) {
@@ -166,6 +345,7 @@ func (m *ServeMux) Serve(b *ResponseBuilder, r *RequestEnvelope) {
 		h = fallbackHandler(err)
 	}
 
+	b.WithRequest(r)
 	h.Serve(b, r)
 	json, _ = jsoniter.Marshal(b.Build())
 	m.logger.Debug(string(json))
@@ -2,11 +2,15 @@ package alexa
 
 import (
 	ctx "context"
+	"errors"
 	"github.com/hamba/pkg/log"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -27,6 +31,69 @@ func TestServer(t *testing.T) {
 	assert.NotEmpty(t, resp)
 }
 
+func TestServer_InvocationContext(t *testing.T) {
+	type key struct{}
+	var got interface{}
+	s := Server{
+		Handler: HandlerFunc(
+			func(b *ResponseBuilder, r *RequestEnvelope) { got = r.InvocationContext().Value(key{}) },
+		),
+	}
+
+	_, err := s.Invoke(ctx.WithValue(ctx.Background(), key{}, "value"), []byte("{}"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "value", got)
+	assert.NotNil(t, (&RequestEnvelope{}).InvocationContext())
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	s := &Server{
+		Handler: HandlerFunc(
+			func(b *ResponseBuilder, r *RequestEnvelope) { b.WithSpeech(r.RequestLocale()) },
+		),
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"request":{"locale":"en-US"}}`))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	resp := &ResponseEnvelope{}
+	err := jsoniter.Unmarshal(rec.Body.Bytes(), resp)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "en-US", resp.Response.OutputSpeech.Text)
+
+	// invalid JSON
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{`))
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// only POST is allowed
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestServer_Rejected(t *testing.T) {
+	s := &Server{
+		Handler: HandlerFunc(
+			func(b *ResponseBuilder, r *RequestEnvelope) { b.Reject(errors.New("test")) },
+		),
+	}
+
+	resp, err := s.Invoke(ctx.Background(), []byte("{}"))
+	assert.True(t, errors.Is(err, ErrRejected))
+	assert.Nil(t, resp)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
 func TestHandler(t *testing.T) {
 	mux := NewServerMux(log.Null)
 	h := HandlerFunc(func(b *ResponseBuilder, r *RequestEnvelope) { b.WithSimpleCard("title", "") })
@@ -108,3 +175,112 @@ func TestServe(t *testing.T) {
 
 	assert.Equal(t, "Fatal error", b.card.Title)
 }
+
+func TestServeMux_HandleAudioPlayer(t *testing.T) {
+	mux := NewServerMux(log.Null)
+	mux.HandleAudioPlayerFunc(func(b *ResponseBuilder, r *RequestEnvelope) {
+		if r.RequestType() != TypeAudioPlayerPlaybackNearlyFinished {
+			return
+		}
+		b.WithSpeech("not allowed").WithSimpleCard("not", "allowed")
+		b.WithAudioPlayerPlay(PlayBehaviorEnqueue, Stream{
+			Token:                 "next",
+			ExpectedPreviousToken: r.Request.Token,
+			URL:                   "https://example.com/next.mp3",
+		}, nil)
+	})
+	mux.HandlePlaybackControllerFunc(func(b *ResponseBuilder, r *RequestEnvelope) {
+		b.WithAudioPlayerStop()
+	})
+
+	payload := `{"version":"1.0","request":{"type":"AudioPlayer.PlaybackNearlyFinished",` +
+		`"requestId":"id","locale":"en-US","token":"current","offsetInMilliseconds":1000}}`
+	r := &RequestEnvelope{}
+	assert.NoError(t, jsoniter.UnmarshalFromString(payload, r))
+	b := &ResponseBuilder{}
+	mux.Serve(b, r)
+	out := b.Build()
+
+	assert.Nil(t, out.Response.OutputSpeech)
+	assert.Nil(t, out.Response.Card)
+	assert.Len(t, out.Response.Directives, 1)
+	assert.Equal(t, DirectiveTypeAudioPlayerPlay, out.Response.Directives[0].Type)
+	assert.Equal(t, "current", out.Response.Directives[0].AudioItem.Stream.ExpectedPreviousToken)
+
+	for _, typ := range append(AudioPlayerRequestTypes, PlaybackControllerRequestTypes...) {
+		_, err := mux.Handler(&RequestEnvelope{Request: &Request{Type: typ}})
+		assert.NoError(t, err, typ)
+	}
+	b = &ResponseBuilder{}
+	mux.Serve(b, &RequestEnvelope{Request: &Request{Type: TypePlaybackControllerPauseCommandIssued}})
+	assert.Equal(t, DirectiveTypeAudioPlayerStop, b.Build().Response.Directives[0].Type)
+}
+
+func TestServeMux_HandleIntentWithSlots(t *testing.T) {
+	mux := NewServerMux(log.Null)
+	mux.HandleIntentWithSlotsFunc("Intent",
+		func(b *ResponseBuilder, r *RequestEnvelope) {
+			b.WithSimpleCard("resolved", r.ResolvedSlotValue("Required")+" "+r.CanonicalSlotValue("Required"))
+		},
+		IntentSlot{Name: "Required"},
+		IntentSlot{
+			Name: "Validated", Optional: true, Validate: true,
+			Elicit: HandlerFunc(func(b *ResponseBuilder, r *RequestEnvelope) { b.WithSpeech("which one?") }),
+		},
+	)
+
+	match := &Resolutions{ResolutionsPerAuthority: []*PerAuthority{{
+		Status: &ResolutionStatus{Code: ResolutionStatusMatch},
+		Values: []*AuthorityValue{{Value: &AuthorityValueValue{Name: "Canonical", ID: "1"}}},
+	}}}
+	noMatch := &Resolutions{ResolutionsPerAuthority: []*PerAuthority{{
+		Status: &ResolutionStatus{Code: ResolutionStatusNoMatch},
+	}}}
+
+	r := &RequestEnvelope{
+		Session: &Session{Attributes: map[string]interface{}{"foo": "bar"}},
+		Request: &Request{
+			Type:   TypeIntentRequest,
+			Intent: Intent{Name: "Intent", Slots: map[string]*Slot{}},
+		},
+	}
+
+	// missing required slot without prompt is delegated
+	b := &ResponseBuilder{}
+	mux.Serve(b, r)
+	res := b.Build()
+	assert.Equal(t, DirectiveTypeDialogDelegate, res.Response.Directives[0].Type)
+	assert.Equal(t, "Intent", res.Response.Directives[0].UpdatedIntent.Name)
+	assert.Equal(t, "bar", res.SessionAttributes["foo"])
+
+	// invalid slot is elicited
+	r.Request.Intent.Slots["Required"] = &Slot{Name: "Required", Value: "value", Resolutions: match}
+	r.Request.Intent.Slots["Validated"] = &Slot{Name: "Validated", Value: "invalid", Resolutions: noMatch}
+	b = &ResponseBuilder{}
+	mux.Serve(b, r)
+	res = b.Build()
+	assert.Equal(t, DirectiveTypeDialogElicitSlot, res.Response.Directives[0].Type)
+	assert.Equal(t, "Validated", res.Response.Directives[0].SlotToElicit)
+	assert.Equal(t, "value", res.Response.Directives[0].UpdatedIntent.Slots["Required"].Value)
+	assert.Nil(t, res.Response.Directives[0].UpdatedIntent.Slots["Required"].Resolutions)
+	assert.Empty(t, res.Response.Directives[0].UpdatedIntent.Slots["Validated"].Value)
+	assert.Equal(t, "which one?", res.Response.OutputSpeech.Text)
+
+	// resolved
+	r.Request.Intent.Slots["Validated"].Resolutions = match
+	b = &ResponseBuilder{}
+	mux.Serve(b, r)
+	res = b.Build()
+	assert.Empty(t, res.Response.Directives)
+	assert.Equal(t, "resolved", res.Response.Card.Title)
+	assert.Equal(t, "value Canonical", res.Response.Card.Content)
+
+	// registering without slots removes them
+	mux.HandleIntentFunc("Intent", func(b *ResponseBuilder, r *RequestEnvelope) {
+		b.WithSimpleCard("plain", "")
+	})
+	r.Request.Intent.Slots = nil
+	b = &ResponseBuilder{}
+	mux.Serve(b, r)
+	assert.Equal(t, "plain", b.Build().Response.Card.Title)
+}
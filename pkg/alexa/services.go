@@ -0,0 +1,317 @@
+package alexa
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Permissions the user grants in the Alexa app, see WithAskForPermissionsConsentCard.
+const (
+	PermissionProfileName          = "alexa::profile:name:read"
+	PermissionProfileGivenName     = "alexa::profile:given_name:read"
+	PermissionProfileEmail         = "alexa::profile:email:read"
+	PermissionProfileMobileNumber  = "alexa::profile:mobile_number:read"
+	PermissionDeviceAddress        = "read::alexa:device:all:address"
+	PermissionCountryAndPostalCode = "read::alexa:device:all:address:country_and_postal_code"
+)
+
+// Service errors.
+var (
+	ErrNoAPIAccess = errors.New("no API endpoint or access token")
+	ErrPermission  = errors.New("missing permission")
+)
+
+// APIError is returned if an Alexa service API rejects a call.
+type APIError struct {
+	StatusCode int
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+// Error returns a string representation of the error.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("alexa api: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("alexa api: status %d: %s %s", e.StatusCode, e.Code, e.Message)
+}
+
+// PermissionError is returned if the user did not grant the permissions needed to call an API.
+type PermissionError struct {
+	Permissions []string
+}
+
+// Error returns a string representation of the error.
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPermission, strings.Join(e.Permissions, ", "))
+}
+
+// Unwrap returns ErrPermission.
+func (e *PermissionError) Unwrap() error {
+	return ErrPermission
+}
+
+// WithAskForPermissionsConsentCard asks the user to grant the permissions in the Alexa app.
+func (b *ResponseBuilder) WithAskForPermissionsConsentCard(permissions ...string) *ResponseBuilder {
+	b.card = &Card{
+		Type:        "AskForPermissionsConsent",
+		Permissions: permissions,
+	}
+	return b
+}
+
+// WithPermissionError sets an AskForPermissionsConsent card if the error is a PermissionError,
+// it returns true if it is.
+func (b *ResponseBuilder) WithPermissionError(err error) bool {
+	var perr *PermissionError
+	if !errors.As(err, &perr) {
+		return false
+	}
+	b.WithAskForPermissionsConsentCard(perr.Permissions...)
+	return true
+}
+
+// callAPI calls the Alexa service API of the request, decoding the JSON response into out if not nil.
+//
+// Forbidden calls return a PermissionError with the given permissions.
+func callAPI(
+	ctx context.Context, client *http.Client, r *RequestEnvelope, method, path string, in, out interface{},
+	permissions ...string,
+) error {
+	sys, err := r.System()
+	if err != nil || sys.APIEndpoint == "" || sys.APIAccessToken == "" {
+		return ErrNoAPIAccess
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := jsoniter.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	u := strings.TrimSuffix(sys.APIEndpoint, "/") + path
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+sys.APIAccessToken)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRequestSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusForbidden && len(permissions) > 0 {
+		return &PermissionError{Permissions: permissions}
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		_ = jsoniter.Unmarshal(data, apiErr)
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	return jsoniter.Unmarshal(data, out)
+}
+
+// deviceID returns the escaped device ID of the request.
+func deviceID(r *RequestEnvelope) (string, error) {
+	sys, err := r.System()
+	if err != nil || sys.Device.DeviceID == "" {
+		return "", NotFoundError{element: "context.System.device.deviceId"}
+	}
+	return url.PathEscape(sys.Device.DeviceID), nil
+}
+
+// DistanceUnit is the distance unit of a device.
+type DistanceUnit string
+
+// Distance units.
+const (
+	DistanceUnitMetric   DistanceUnit = "METRIC"
+	DistanceUnitImperial DistanceUnit = "IMPERIAL"
+)
+
+// TemperatureUnit is the temperature unit of a device.
+type TemperatureUnit string
+
+// Temperature units.
+const (
+	TemperatureUnitCelsius    TemperatureUnit = "CELSIUS"
+	TemperatureUnitFahrenheit TemperatureUnit = "FAHRENHEIT"
+)
+
+// SettingsClient reads the settings of the device of a request, it needs no permissions.
+// https://developer.amazon.com/en-US/docs/alexa/smapi/alexa-settings-api-reference.html
+type SettingsClient struct {
+	Client *http.Client
+}
+
+// NewSettingsClient returns a SettingsClient.
+func NewSettingsClient() *SettingsClient {
+	return &SettingsClient{Client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (c *SettingsClient) setting(ctx context.Context, r *RequestEnvelope, name string, out interface{}) error {
+	id, err := deviceID(r)
+	if err != nil {
+		return err
+	}
+	return callAPI(ctx, c.Client, r, http.MethodGet, "/v2/devices/"+id+"/settings/"+name, nil, out)
+}
+
+// TimeZone returns the time zone of the device, e.g. "Europe/Berlin".
+func (c *SettingsClient) TimeZone(ctx context.Context, r *RequestEnvelope) (string, error) {
+	var tz string
+	err := c.setting(ctx, r, "System.timeZone", &tz)
+	return tz, err
+}
+
+// Location returns the time zone of the device as location.
+func (c *SettingsClient) Location(ctx context.Context, r *RequestEnvelope) (*time.Location, error) {
+	tz, err := c.TimeZone(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return time.LoadLocation(tz)
+}
+
+// DistanceUnits returns the distance units of the device.
+func (c *SettingsClient) DistanceUnits(ctx context.Context, r *RequestEnvelope) (DistanceUnit, error) {
+	var u DistanceUnit
+	err := c.setting(ctx, r, "System.distanceUnits", &u)
+	return u, err
+}
+
+// TemperatureUnit returns the temperature unit of the device.
+func (c *SettingsClient) TemperatureUnit(ctx context.Context, r *RequestEnvelope) (TemperatureUnit, error) {
+	var u TemperatureUnit
+	err := c.setting(ctx, r, "System.temperatureUnit", &u)
+	return u, err
+}
+
+// PhoneNumber is the mobile number of a customer.
+type PhoneNumber struct {
+	CountryCode string `json:"countryCode"`
+	PhoneNumber string `json:"phoneNumber"`
+}
+
+// ProfileClient reads the profile of the customer of a request, each field needs its permission.
+// https://developer.amazon.com/en-US/docs/alexa/custom-skills/request-customer-contact-information-for-use-in-your-skill.html
+type ProfileClient struct {
+	Client *http.Client
+}
+
+// NewProfileClient returns a ProfileClient.
+func NewProfileClient() *ProfileClient {
+	return &ProfileClient{Client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (c *ProfileClient) profile(
+	ctx context.Context, r *RequestEnvelope, name, permission string, out interface{},
+) error {
+	path := "/v2/accounts/~current/settings/Profile." + name
+	return callAPI(ctx, c.Client, r, http.MethodGet, path, nil, out, permission)
+}
+
+// Name returns the full name of the customer.
+func (c *ProfileClient) Name(ctx context.Context, r *RequestEnvelope) (string, error) {
+	var name string
+	err := c.profile(ctx, r, "name", PermissionProfileName, &name)
+	return name, err
+}
+
+// GivenName returns the given name of the customer.
+func (c *ProfileClient) GivenName(ctx context.Context, r *RequestEnvelope) (string, error) {
+	var name string
+	err := c.profile(ctx, r, "givenName", PermissionProfileGivenName, &name)
+	return name, err
+}
+
+// Email returns the email address of the customer.
+func (c *ProfileClient) Email(ctx context.Context, r *RequestEnvelope) (string, error) {
+	var email string
+	err := c.profile(ctx, r, "email", PermissionProfileEmail, &email)
+	return email, err
+}
+
+// MobileNumber returns the mobile number of the customer.
+func (c *ProfileClient) MobileNumber(ctx context.Context, r *RequestEnvelope) (PhoneNumber, error) {
+	var number PhoneNumber
+	err := c.profile(ctx, r, "mobileNumber", PermissionProfileMobileNumber, &number)
+	return number, err
+}
+
+// Address is the address of a device, only country and postal code are set if that is all requested.
+type Address struct {
+	AddressLine1     string `json:"addressLine1,omitempty"`
+	AddressLine2     string `json:"addressLine2,omitempty"`
+	AddressLine3     string `json:"addressLine3,omitempty"`
+	City             string `json:"city,omitempty"`
+	StateOrRegion    string `json:"stateOrRegion,omitempty"`
+	DistrictOrCounty string `json:"districtOrCounty,omitempty"`
+	PostalCode       string `json:"postalCode,omitempty"`
+	CountryCode      string `json:"countryCode,omitempty"`
+}
+
+// AddressClient reads the address of the device of a request.
+// https://developer.amazon.com/en-US/docs/alexa/custom-skills/device-address-api.html
+type AddressClient struct {
+	Client *http.Client
+}
+
+// NewAddressClient returns an AddressClient.
+func NewAddressClient() *AddressClient {
+	return &AddressClient{Client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Address returns the full address of the device, it needs PermissionDeviceAddress.
+func (c *AddressClient) Address(ctx context.Context, r *RequestEnvelope) (*Address, error) {
+	return c.address(ctx, r, "", PermissionDeviceAddress)
+}
+
+// CountryAndPostalCode returns the country and postal code of the device, it needs PermissionCountryAndPostalCode.
+func (c *AddressClient) CountryAndPostalCode(ctx context.Context, r *RequestEnvelope) (*Address, error) {
+	return c.address(ctx, r, "/countryAndPostalCode", PermissionCountryAndPostalCode)
+}
+
+func (c *AddressClient) address(ctx context.Context, r *RequestEnvelope, path, permission string) (*Address, error) {
+	id, err := deviceID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var addr Address
+	path = "/v1/devices/" + id + "/settings/address" + path
+	if err := callAPI(ctx, c.Client, r, http.MethodGet, path, nil, &addr, permission); err != nil {
+		return nil, err
+	}
+	return &addr, nil
+}
@@ -0,0 +1,136 @@
+package alexa_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/alexatest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettingsClient(t *testing.T) {
+	srv := alexatest.NewServicesServer()
+	defer srv.Close()
+	c := alexa.NewSettingsClient()
+	ctx := context.Background()
+	r := srv.WithAPI(&alexa.RequestEnvelope{})
+
+	tz, err := c.TimeZone(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", tz)
+
+	loc, err := c.Location(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", loc.String())
+
+	du, err := c.DistanceUnits(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, alexa.DistanceUnitMetric, du)
+
+	tu, err := c.TemperatureUnit(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, alexa.TemperatureUnitCelsius, tu)
+}
+
+func TestSettingsClient_Errors(t *testing.T) {
+	srv := alexatest.NewServicesServer()
+	defer srv.Close()
+	c := alexa.NewSettingsClient()
+	ctx := context.Background()
+
+	_, err := c.TimeZone(ctx, &alexa.RequestEnvelope{})
+	var nerr alexa.NotFoundError
+	assert.True(t, errors.As(err, &nerr))
+
+	r := srv.WithAPI(&alexa.RequestEnvelope{})
+	r.Context.System.APIAccessToken = ""
+	_, err = c.TimeZone(ctx, r)
+	assert.True(t, errors.Is(err, alexa.ErrNoAPIAccess))
+
+	r = srv.WithAPI(r)
+	r.Context.System.Device.DeviceID = "unknown"
+	_, err = c.TimeZone(ctx, r)
+	var apiErr *alexa.APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "DEVICE_NOT_FOUND", apiErr.Code)
+}
+
+func TestProfileClient(t *testing.T) {
+	srv := alexatest.NewServicesServer()
+	defer srv.Close()
+	c := alexa.NewProfileClient()
+	ctx := context.Background()
+	r := srv.WithAPI(&alexa.RequestEnvelope{})
+
+	name, err := c.Name(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "Max Mustermann", name)
+
+	given, err := c.GivenName(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "Max", given)
+
+	email, err := c.Email(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "max@example.com", email)
+
+	number, err := c.MobileNumber(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, alexa.PhoneNumber{CountryCode: "+49", PhoneNumber: "1701234567"}, number)
+}
+
+func TestProfileClient_PermissionError(t *testing.T) {
+	srv := alexatest.NewServicesServer()
+	defer srv.Close()
+	srv.Deny(alexa.PermissionProfileEmail)
+	c := alexa.NewProfileClient()
+	r := srv.WithAPI(&alexa.RequestEnvelope{})
+
+	_, err := c.Email(context.Background(), r)
+
+	assert.True(t, errors.Is(err, alexa.ErrPermission))
+	assert.EqualError(t, err, "missing permission: alexa::profile:email:read")
+
+	b := &alexa.ResponseBuilder{}
+	assert.True(t, b.WithPermissionError(err))
+	assert.False(t, b.WithPermissionError(errors.New("other")))
+	resp := b.Build()
+	assert.Equal(t, &alexa.Card{
+		Type:        "AskForPermissionsConsent",
+		Permissions: []string{alexa.PermissionProfileEmail},
+	}, resp.Response.Card)
+}
+
+func TestAddressClient(t *testing.T) {
+	srv := alexatest.NewServicesServer()
+	defer srv.Close()
+	c := alexa.NewAddressClient()
+	ctx := context.Background()
+	r := srv.WithAPI(&alexa.RequestEnvelope{})
+
+	addr, err := c.Address(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, &alexa.Address{
+		AddressLine1: "Alexanderplatz 1",
+		City:         "Berlin",
+		PostalCode:   "10178",
+		CountryCode:  "DE",
+	}, addr)
+
+	addr, err = c.CountryAndPostalCode(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, &alexa.Address{PostalCode: "10178", CountryCode: "DE"}, addr)
+
+	srv.Deny(alexa.PermissionDeviceAddress)
+	_, err = c.Address(ctx, r)
+	var perr *alexa.PermissionError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, []string{alexa.PermissionDeviceAddress}, perr.Permissions)
+
+	_, err = c.CountryAndPostalCode(ctx, r)
+	assert.NoError(t, err)
+}
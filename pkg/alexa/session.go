@@ -0,0 +1,138 @@
+package alexa
+
+import (
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// SessionAttributeVariants is the session attribute storing the l10n variants used recently.
+const SessionAttributeVariants = "L10nVariants"
+
+// SessionState is a typed view on the session attributes of a request.
+//
+// Values are round-tripped through JSON, so structs can be stored and loaded
+// the same way Alexa sends them back with the next request of the session.
+type SessionState struct {
+	attrs map[string]interface{}
+}
+
+// NewSessionState returns the session state of the request.
+func NewSessionState(r *RequestEnvelope) *SessionState {
+	s := &SessionState{attrs: map[string]interface{}{}}
+	if r.Session == nil {
+		return s
+	}
+
+	for k, v := range r.Session.Attributes {
+		s.attrs[k] = v
+	}
+	return s
+}
+
+// Has returns true if the key is set.
+func (s *SessionState) Has(key string) bool {
+	_, ok := s.attrs[key]
+	return ok
+}
+
+// Get decodes the value of the key into v.
+func (s *SessionState) Get(key string, v interface{}) error {
+	val, ok := s.attrs[key]
+	if !ok {
+		return &NotFoundError{"session attribute", key}
+	}
+
+	return decodeAttribute(val, v)
+}
+
+// Set sets the key to the JSON representation of v.
+func (s *SessionState) Set(key string, v interface{}) error {
+	val, err := encodeAttribute(v)
+	if err != nil {
+		return err
+	}
+
+	s.attrs[key] = val
+	return nil
+}
+
+// Delete removes the key.
+func (s *SessionState) Delete(key string) {
+	delete(s.attrs, key)
+}
+
+// Attributes returns the session attributes.
+func (s *SessionState) Attributes() map[string]interface{} {
+	return s.attrs
+}
+
+// WithSessionState sets the session attributes from the state on the response.
+func (b *ResponseBuilder) WithSessionState(s *SessionState) *ResponseBuilder {
+	if len(s.Attributes()) == 0 {
+		return b.WithSessionAttributes(nil)
+	}
+	return b.WithSessionAttributes(s.Attributes())
+}
+
+// VariantHistory returns the l10n variants used recently in the session, see l10n.NewRecentSelector.
+func (s *SessionState) VariantHistory() l10n.History {
+	return sessionHistory{state: s}
+}
+
+// WithVariantHistory sets a selector on the locale which avoids the last variants used in the session,
+// see l10n.NewRecentSelector. The locale must be request-scoped, as returned by l10n.LocaleRegistry.Resolve,
+// so sessions do not share their history.
+//
+// Locales without an injectable Selector are left unchanged.
+func (s *SessionState) WithVariantHistory(loc l10n.LocaleInstance, last int) l10n.LocaleInstance {
+	if setter, ok := loc.(l10n.SelectorSetter); ok {
+		setter.SetSelector(l10n.NewRecentSelector(s.VariantHistory(), last, nil))
+	}
+	return loc
+}
+
+// sessionHistory stores the recent variants of all keys in a single session attribute.
+type sessionHistory struct {
+	state *SessionState
+}
+
+func (h sessionHistory) recent() map[string][]int {
+	recent := map[string][]int{}
+	_ = h.state.Get(SessionAttributeVariants, &recent)
+	return recent
+}
+
+// Recent returns the recently used variants of the key.
+func (h sessionHistory) Recent(key string) []int {
+	return h.recent()[key]
+}
+
+// SetRecent sets the recently used variants of the key.
+func (h sessionHistory) SetRecent(key string, variants []int) {
+	recent := h.recent()
+	recent[key] = variants
+	_ = h.state.Set(SessionAttributeVariants, recent)
+}
+
+// encodeAttribute returns the JSON representation of v as generic value.
+func encodeAttribute(v interface{}) (interface{}, error) {
+	b, err := jsoniter.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var val interface{}
+	if err := jsoniter.Unmarshal(b, &val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// decodeAttribute decodes the generic value into v.
+func decodeAttribute(val, v interface{}) error {
+	b, err := jsoniter.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return jsoniter.Unmarshal(b, v)
+}
@@ -0,0 +1,117 @@
+package alexa
+
+import (
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/stretchr/testify/assert"
+)
+
+type testState struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags"`
+}
+
+func TestSessionState(t *testing.T) {
+	r := &RequestEnvelope{}
+	s := NewSessionState(r)
+	assert.Empty(t, s.Attributes())
+
+	var st testState
+	err := s.Get("state", &st)
+	assert.Error(t, err)
+	assert.False(t, s.Has("state"))
+
+	err = s.Set("state", testState{Name: "foo", Count: 2, Tags: []string{"a"}})
+	assert.NoError(t, err)
+	assert.True(t, s.Has("state"))
+
+	err = s.Get("state", &st)
+	assert.NoError(t, err)
+	assert.Equal(t, testState{Name: "foo", Count: 2, Tags: []string{"a"}}, st)
+
+	err = s.Set("invalid", func() {})
+	assert.Error(t, err)
+
+	s.Delete("state")
+	assert.False(t, s.Has("state"))
+}
+
+func TestSessionState_RoundTrip(t *testing.T) {
+	s := NewSessionState(&RequestEnvelope{})
+	err := s.Set("state", testState{Name: "foo", Count: 2})
+	assert.NoError(t, err)
+	err = s.Set("simple", "value")
+	assert.NoError(t, err)
+
+	b := &ResponseBuilder{}
+	b.WithSessionState(s)
+	res := b.Build()
+
+	// Alexa sends the attributes back with the next request
+	r := &RequestEnvelope{Session: &Session{Attributes: res.SessionAttributes}}
+	s = NewSessionState(r)
+
+	var st testState
+	err = s.Get("state", &st)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", st.Name)
+	assert.Equal(t, 2, st.Count)
+
+	var simple string
+	err = s.Get("simple", &simple)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", simple)
+
+	// changes do not affect the request
+	s.Delete("simple")
+	assert.Contains(t, r.Session.Attributes, "simple")
+
+	// empty state sends no attributes
+	s.Delete("state")
+	b.WithSessionState(s)
+	assert.Nil(t, b.Build().SessionAttributes)
+}
+
+func TestSessionState_VariantHistory(t *testing.T) {
+	s := NewSessionState(&RequestEnvelope{})
+	h := s.VariantHistory()
+	assert.Empty(t, h.Recent("Launch_SSML"))
+
+	h.SetRecent("Launch_SSML", []int{2, 0})
+	h.SetRecent("Help_SSML", []int{1})
+
+	// Alexa sends the attributes back with the next request
+	res := (&ResponseBuilder{}).WithSessionState(s).Build()
+	s = NewSessionState(&RequestEnvelope{Session: &Session{Attributes: res.SessionAttributes}})
+
+	sel := l10n.NewRecentSelector(s.VariantHistory(), 2, nil)
+	assert.Equal(t, 1, sel.Select("Launch_SSML", 3))
+	assert.Equal(t, []int{0, 1}, s.VariantHistory().Recent("Launch_SSML"))
+	assert.Equal(t, []int{1}, s.VariantHistory().Recent("Help_SSML"))
+}
+
+func TestSessionState_WithVariantHistory(t *testing.T) {
+	en := l10n.NewLocale("en-US")
+	en.Set("Launch_SSML", []string{"Hi", "Hello"})
+	r := l10n.NewRegistry()
+	assert.NoError(t, r.Register(en, l10n.AsDefault()))
+
+	s1 := NewSessionState(&RequestEnvelope{})
+	s2 := NewSessionState(&RequestEnvelope{})
+	loc1, err := r.Resolve("en-US")
+	assert.NoError(t, err)
+	loc2, err := r.Resolve("en-US")
+	assert.NoError(t, err)
+	loc1 = s1.WithVariantHistory(loc1, 1)
+	loc2 = s2.WithVariantHistory(loc2, 1)
+
+	first := loc1.GetAny("Launch_SSML")
+	assert.NotEqual(t, first, loc1.GetAny("Launch_SSML"))
+	assert.Len(t, s1.VariantHistory().Recent("Launch_SSML"), 1)
+	assert.Empty(t, s2.VariantHistory().Recent("Launch_SSML"))
+
+	loc2.GetAny("Launch_SSML")
+	assert.Len(t, s2.VariantHistory().Recent("Launch_SSML"), 1)
+}
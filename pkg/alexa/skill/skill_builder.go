@@ -3,7 +3,7 @@ package skill
 import (
 	"fmt"
 
-	"github.com/drpsychick/go-alexa-lambda/l10n"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
 )
 
 // Flags for alexa.Privacy.
@@ -117,8 +117,11 @@ func (s *SkillBuilder) WithDefaultLocaleTestingInstructions(instructions string)
 	return s
 }
 
-// WithModel creates and returns a new modelBuilder attached to the skill.
+// WithModel attaches a new modelBuilder to the skill, unless it already has one.
 func (s *SkillBuilder) WithModel() *SkillBuilder {
+	if s.model != nil {
+		return s
+	}
 	s.model = NewModelBuilder().
 		WithLocaleRegistry(s.registry)
 	return s
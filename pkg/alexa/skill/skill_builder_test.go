@@ -3,9 +3,9 @@ package skill_test
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/drpsychick/go-alexa-lambda/l10n"
-	"github.com/drpsychick/go-alexa-lambda/skill"
-	"github.com/drpsychick/go-alexa-lambda/ssml"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/l10n"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/skill"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/ssml"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -0,0 +1,235 @@
+package ssml
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits of the speech of a response.
+const (
+	MaxChars         = 8000
+	MaxAudioDuration = 240 * time.Second
+)
+
+// ErrBudget is returned for speech exceeding the limits of a response.
+var ErrBudget = errors.New("speech over budget")
+
+// Usage is the measured size of speech.
+type Usage struct {
+	Chars         int
+	Audio         int
+	AudioDuration time.Duration
+}
+
+// Exceeded returns true if the usage exceeds any limit.
+func (u Usage) Exceeded() bool {
+	return u.Chars > MaxChars || u.Audio > MaxAudio || u.AudioDuration > MaxAudioDuration
+}
+
+// BudgetError is returned for speech exceeding the limits, see Budget.
+type BudgetError struct {
+	Usage Usage
+}
+
+// Error returns a string representation of the error.
+func (e *BudgetError) Error() string {
+	var msgs []string
+	if e.Usage.Chars > MaxChars {
+		msgs = append(msgs, fmt.Sprintf("%d characters, at most %d", e.Usage.Chars, MaxChars))
+	}
+	if e.Usage.Audio > MaxAudio {
+		msgs = append(msgs, fmt.Sprintf("%d audio elements, at most %d", e.Usage.Audio, MaxAudio))
+	}
+	if e.Usage.AudioDuration > MaxAudioDuration {
+		msgs = append(msgs, fmt.Sprintf("%s of audio, at most %s", e.Usage.AudioDuration, MaxAudioDuration))
+	}
+	return fmt.Sprintf("%s: %s", ErrBudget, strings.Join(msgs, ", "))
+}
+
+// Unwrap returns ErrBudget.
+func (e *BudgetError) Unwrap() error {
+	return ErrBudget
+}
+
+// BudgetConfig contains the options for a Budget.
+type BudgetConfig struct {
+	AudioDuration func(src string) time.Duration
+}
+
+// BudgetOptFunc defines the functions to be passed to NewBudget.
+type BudgetOptFunc func(cfg *BudgetConfig)
+
+// WithAudioDuration sets the function returning the duration of an audio file,
+// the duration of audio is not measured without it.
+func WithAudioDuration(fn func(src string) time.Duration) BudgetOptFunc {
+	return func(cfg *BudgetConfig) {
+		cfg.AudioDuration = fn
+	}
+}
+
+// Budget measures speech against the limits of a response: MaxChars characters,
+// MaxAudio audio elements and MaxAudioDuration of audio.
+type Budget struct {
+	cfg BudgetConfig
+}
+
+// NewBudget returns a budget.
+func NewBudget(opts ...BudgetOptFunc) *Budget {
+	var cfg BudgetConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Budget{cfg: cfg}
+}
+
+// Measure returns the usage of the rendered element.
+func (b *Budget) Measure(e *Element) Usage {
+	u := Usage{Chars: utf8.RuneCountInString(e.String())}
+	b.measureAudio(e, &u)
+	return u
+}
+
+func (b *Budget) measureAudio(e *Element, u *Usage) {
+	if e.Name == "audio" {
+		u.Audio++
+		if b.cfg.AudioDuration != nil {
+			src, _ := e.Attr("src")
+			u.AudioDuration += b.cfg.AudioDuration(src)
+		}
+	}
+	for _, c := range e.Children {
+		if n, ok := c.(*Element); ok {
+			b.measureAudio(n, u)
+		}
+	}
+}
+
+// Check returns a BudgetError if the SSML exceeds the limits.
+func (b *Budget) Check(s string) error {
+	e, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	if u := b.Measure(e); u.Exceeded() {
+		return &BudgetError{Usage: u}
+	}
+	return nil
+}
+
+// CheckText returns a BudgetError if the plain text exceeds the limits.
+func (b *Budget) CheckText(s string) error {
+	if u := (Usage{Chars: utf8.RuneCountInString(s)}); u.Exceeded() {
+		return &BudgetError{Usage: u}
+	}
+	return nil
+}
+
+// Truncate returns the SSML truncated at sentence boundaries to the limits, unchanged if within them.
+//
+// The speech is kept in order up to the first sentence, audio or element which exceeds a limit,
+// text is split into sentences and elements are truncated within.
+func (b *Budget) Truncate(s string) (string, error) {
+	e, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if !b.Measure(e).Exceeded() {
+		return s, nil
+	}
+
+	t := &truncater{budget: b, root: NewElement(e.Name, e.Attrs)}
+	t.fill(t.root, e.Children)
+	return t.root.String(), nil
+}
+
+// TruncateText returns the plain text truncated at sentence boundaries to the limits, unchanged if within them.
+func (b *Budget) TruncateText(s string) string {
+	if b.CheckText(s) == nil {
+		return s
+	}
+
+	var text string
+	for _, sentence := range splitSentences(s) {
+		if b.CheckText(text+sentence) != nil {
+			break
+		}
+		text += sentence
+	}
+	return strings.TrimSpace(text)
+}
+
+type truncater struct {
+	budget *Budget
+	root   *Element
+}
+
+func (t *truncater) fits() bool {
+	return !t.budget.Measure(t.root).Exceeded()
+}
+
+// fill adds the children to dst as long as the root fits, it returns false once a child did not fit.
+func (t *truncater) fill(dst *Element, children []Node) bool {
+	for _, c := range children {
+		dst.Add(c)
+		if t.fits() {
+			continue
+		}
+		dst.Children = dst.Children[:len(dst.Children)-1]
+
+		switch n := c.(type) {
+		case Text:
+			var text Text
+			dst.Add(text)
+			for _, sentence := range splitSentences(string(n)) {
+				dst.Children[len(dst.Children)-1] = text + Text(sentence)
+				if !t.fits() {
+					break
+				}
+				text += Text(sentence)
+			}
+			dst.Children[len(dst.Children)-1] = text
+			if strings.TrimSpace(string(text)) == "" {
+				dst.Children = dst.Children[:len(dst.Children)-1]
+			}
+		case *Element:
+			if voidElements[n.Name] || textElements[n.Name] {
+				break
+			}
+			e := NewElement(n.Name, n.Attrs)
+			dst.Add(e)
+			t.fill(e, n.Children)
+			if len(e.Children) == 0 || !t.fits() {
+				dst.Children = dst.Children[:len(dst.Children)-1]
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// splitSentences splits text after sentence ending punctuation followed by white space.
+func splitSentences(s string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(s)-1; i++ {
+		if !strings.ContainsRune(".!?", rune(s[i])) || !isSpace(s[i+1]) {
+			continue
+		}
+		for i+1 < len(s) && isSpace(s[i+1]) {
+			i++
+		}
+		sentences = append(sentences, s[start:i+1])
+		start = i + 1
+	}
+	if start < len(s) {
+		sentences = append(sentences, s[start:])
+	}
+	return sentences
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\t' || c == '\r'
+}
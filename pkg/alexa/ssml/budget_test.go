@@ -0,0 +1,106 @@
+package ssml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBudget_Check(t *testing.T) {
+	audio := `<audio src="soundbank://a"/>`
+	b := NewBudget(WithAudioDuration(func(src string) time.Duration {
+		return time.Minute
+	}))
+
+	tests := []struct {
+		name    string
+		ssml    string
+		wantErr error
+	}{
+		{"WithinBudget", "<speak>" + strings.Repeat(audio, 4) + "Hello</speak>", nil},
+		{"TooLong", "<speak>" + strings.Repeat("a", MaxChars) + "</speak>", ErrBudget},
+		{"TooManyAudio", "<speak>" + strings.Repeat(audio, 6) + "</speak>", ErrBudget},
+		{"AudioTooLong", "<speak>" + strings.Repeat(audio, 5) + "</speak>", ErrBudget},
+		{"Invalid", "<speak>", ErrUnclosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := b.Check(tt.ssml); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBudget_Measure(t *testing.T) {
+	e, _ := Parse(`<speak>Häh<audio src="soundbank://a"/></speak>`)
+
+	got := NewBudget().Measure(e)
+
+	want := Usage{Chars: 46, Audio: 1}
+	if got != want {
+		t.Errorf("Measure() = %v, want %v", got, want)
+	}
+}
+
+func TestBudgetError_Error(t *testing.T) {
+	err := &BudgetError{Usage: Usage{Chars: 9000, Audio: 6, AudioDuration: time.Second}}
+
+	want := "speech over budget: 9000 characters, at most 8000, 6 audio elements, at most 5"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+}
+
+func TestBudget_Truncate(t *testing.T) {
+	sentence := strings.Repeat("a", 99) + ". "
+	b := NewBudget()
+
+	tests := []struct {
+		name      string
+		ssml      string
+		wantLen   int
+		wantStart string
+	}{
+		{"Unchanged", "<speak>Hello.  World.</speak>", 29, "<speak>Hello.  World."},
+		{"Text", "<speak>" + strings.Repeat(sentence, 100) + "</speak>", 7 + 79*101 + 8, "<speak>aaa"},
+		{"Paragraph", "<speak><p>" + strings.Repeat(sentence, 100) + "</p></speak>", 7 + 3 + 78*101 + 4 + 8, "<speak><p>aaa"},
+		{"Audio", "<speak>" + strings.Repeat(`<audio src="soundbank://a"/>`, 6) + "</speak>", 7 + 5*28 + 8, "<speak><audio"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.Truncate(tt.ssml)
+			if err != nil {
+				t.Fatalf("Truncate() error = %v", err)
+			}
+			if len(got) != tt.wantLen || !strings.HasPrefix(got, tt.wantStart) {
+				t.Errorf("Truncate() = %d %v, want %d %v", len(got), got[:20], tt.wantLen, tt.wantStart)
+			}
+			if err := b.Check(got); err != nil {
+				t.Errorf("Truncate() is over budget: %v", err)
+			}
+		})
+	}
+}
+
+func TestBudget_TruncateText(t *testing.T) {
+	sentence := strings.Repeat("a", 99) + ". "
+	b := NewBudget()
+
+	if got := b.TruncateText("Hello. World."); got != "Hello. World." {
+		t.Errorf("TruncateText() = %v, want unchanged", got)
+	}
+	if got := b.TruncateText(strings.Repeat(sentence, 100)); len(got) != 79*101-1 {
+		t.Errorf("TruncateText() = %d characters, want %d", len(got), 79*101-1)
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	got := splitSentences("One. Two!  Three?Four 3.5 five.")
+
+	want := []string{"One. ", "Two!  ", "Three?Four 3.5 five."}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("splitSentences() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,429 @@
+package ssml
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Validation errors.
+var (
+	ErrNesting      = errors.New("invalid nesting")
+	ErrAttribute    = errors.New("invalid attribute")
+	ErrTooManyAudio = errors.New("too many audio elements")
+	ErrUnclosed     = errors.New("unclosed element")
+)
+
+// Limits of Alexa.
+const (
+	// MaxAudio is the maximum number of audio elements in a response.
+	MaxAudio = 5
+	// MaxBreak is the maximum duration of a break.
+	MaxBreak = 10 * time.Second
+)
+
+// Node is an element or text of SSML.
+type Node interface {
+	render(b *strings.Builder)
+}
+
+// Text is text, escaped when rendered.
+type Text string
+
+func (t Text) render(b *strings.Builder) {
+	b.WriteString(escapeText(string(t)))
+}
+
+// Raw is SSML, rendered as is and not validated.
+type Raw string
+
+func (r Raw) render(b *strings.Builder) {
+	b.WriteString(string(r))
+}
+
+// Attr is an attribute of an element.
+type Attr struct {
+	Name  string
+	Value string
+}
+
+// Element is an SSML element with its attributes and children.
+type Element struct {
+	Name     string
+	Attrs    []Attr
+	Children []Node
+}
+
+// NewElement returns an element with the given children.
+func NewElement(name string, attrs []Attr, children ...Node) *Element {
+	return &Element{Name: name, Attrs: attrs, Children: children}
+}
+
+// Attr returns the value of the attribute.
+func (e *Element) Attr(name string) (string, bool) {
+	for _, a := range e.Attrs {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// Add appends the children to the element.
+func (e *Element) Add(children ...Node) *Element {
+	e.Children = append(e.Children, children...)
+	return e
+}
+
+// String renders the element.
+func (e *Element) String() string {
+	var b strings.Builder
+	e.render(&b)
+	return b.String()
+}
+
+func (e *Element) render(b *strings.Builder) {
+	b.WriteString("<" + e.Name)
+	for _, a := range e.Attrs {
+		b.WriteString(" " + a.Name + `="` + escapeAttr(a.Value) + `"`)
+	}
+	if voidElements[e.Name] && len(e.Children) == 0 {
+		b.WriteString("/>")
+		return
+	}
+	b.WriteString(">")
+	for _, c := range e.Children {
+		c.render(b)
+	}
+	b.WriteString("</" + e.Name + ">")
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+)
+
+// Escape returns the text with the characters escaped which are markup in SSML,
+// e.g. to pass plain text to the functions taking raw text like SayAs.
+func Escape(s string) string {
+	return escapeText(s)
+}
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
+
+// voidElements have no content and are rendered self-closing.
+var voidElements = map[string]bool{
+	"audio": true,
+	"break": true,
+}
+
+// textElements may only contain text.
+var textElements = map[string]bool{
+	"phoneme": true,
+	"say-as":  true,
+	"sub":     true,
+	"w":       true,
+}
+
+// forbiddenAncestors are the elements that must not contain the element at any depth.
+var forbiddenAncestors = map[string][]string{
+	"speak": {"speak"},
+	"p":     {"p", "s"},
+	"s":     {"s"},
+	// the Alexa voice only features do not work with Amazon Polly voices
+	"amazon:emotion": {"voice"},
+	"amazon:domain":  {"voice"},
+	"voice":          {"amazon:emotion", "amazon:domain"},
+}
+
+// Validate checks the element and its children against the nesting rules and limits of Alexa.
+func Validate(e *Element) error {
+	audio := 0
+	if err := validate(e, nil, &audio); err != nil {
+		return err
+	}
+	if audio > MaxAudio {
+		return fmt.Errorf("%w: %d, at most %d", ErrTooManyAudio, audio, MaxAudio)
+	}
+	return nil
+}
+
+func validate(e *Element, ancestors []string, audio *int) error { //nolint:cyclop
+	for _, a := range ancestors {
+		for _, f := range forbiddenAncestors[e.Name] {
+			if a == f {
+				return fmt.Errorf("%w: <%s> in <%s>", ErrNesting, e.Name, a)
+			}
+		}
+	}
+
+	switch e.Name {
+	case "audio":
+		*audio++
+		if src, _ := e.Attr("src"); !strings.HasPrefix(src, "https://") && !strings.HasPrefix(src, "soundbank://") {
+			return fmt.Errorf("%w: audio src '%s' must be https or soundbank", ErrAttribute, src)
+		}
+	case "break":
+		if t, ok := e.Attr("time"); ok {
+			d, err := parseBreakTime(t)
+			if err != nil || d > MaxBreak {
+				return fmt.Errorf("%w: break time '%s' must not exceed %s", ErrAttribute, t, MaxBreak)
+			}
+		}
+	}
+
+	ancestors = append(ancestors, e.Name)
+	for _, c := range e.Children {
+		switch n := c.(type) {
+		case *Element:
+			if voidElements[e.Name] || textElements[e.Name] {
+				return fmt.Errorf("%w: <%s> in <%s>", ErrNesting, n.Name, e.Name)
+			}
+			if err := validate(n, ancestors, audio); err != nil {
+				return err
+			}
+		case Text:
+			if voidElements[e.Name] && n != "" {
+				return fmt.Errorf("%w: text in <%s>", ErrNesting, e.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// parseBreakTime parses a break time in "s" or "ms".
+func parseBreakTime(t string) (time.Duration, error) {
+	if !strings.HasSuffix(t, "s") {
+		return 0, fmt.Errorf("%w: missing unit", ErrAttribute)
+	}
+	return time.ParseDuration(t)
+}
+
+func newDomain(domain AmazonDomain, children ...Node) *Element {
+	return NewElement("amazon:domain", []Attr{{"name", string(domain)}}, children...)
+}
+
+func newEffect(effect AmazonEffect, children ...Node) *Element {
+	return NewElement("amazon:effect", []Attr{{"name", string(effect)}}, children...)
+}
+
+func newEmotion(name AmazonEmotion, intensity AmazonEmotionIntensity, children ...Node) *Element {
+	return NewElement("amazon:emotion", []Attr{{"name", string(name)}, {"intensity", string(intensity)}}, children...)
+}
+
+func newAudio(src string) *Element {
+	return NewElement("audio", []Attr{{"src", src}})
+}
+
+func newBreak(strength BreakStrength, duration string) *Element {
+	var attrs []Attr
+	if strength != "" {
+		attrs = append(attrs, Attr{"strength", string(strength)})
+	}
+	if duration != "" {
+		attrs = append(attrs, Attr{"time", duration})
+	}
+	return NewElement("break", attrs)
+}
+
+func newEmphasis(level EmphasisLevel, children ...Node) *Element {
+	var attrs []Attr
+	if level != "" {
+		attrs = append(attrs, Attr{"level", string(level)})
+	}
+	return NewElement("emphasis", attrs, children...)
+}
+
+func newLang(language string, children ...Node) *Element {
+	return NewElement("lang", []Attr{{"xml:lang", language}}, children...)
+}
+
+func newPhoneme(alphabet PhonemeAlphabet, ph string, children ...Node) *Element {
+	return NewElement("phoneme", []Attr{{"alphabet", string(alphabet)}, {"ph", ph}}, children...)
+}
+
+func newProsody(rate ProsodyRate, pitch ProsodyPitch, volume ProsodyVolume, children ...Node) *Element {
+	var attrs []Attr
+	if rate != "" {
+		attrs = append(attrs, Attr{"rate", string(rate)})
+	}
+	if pitch != "" {
+		attrs = append(attrs, Attr{"pitch", string(pitch)})
+	}
+	if volume != "" {
+		attrs = append(attrs, Attr{"volume", string(volume)})
+	}
+	return NewElement("prosody", attrs, children...)
+}
+
+func newSayAs(interpretAs SayAsInterpretAs, format string, children ...Node) *Element {
+	attrs := []Attr{{"interpret-as", string(interpretAs)}}
+	if interpretAs == SayAsInterpretAsDate && format != "" {
+		attrs = append(attrs, Attr{"format", format})
+	}
+	return NewElement("say-as", attrs, children...)
+}
+
+func newSub(alias string, children ...Node) *Element {
+	return NewElement("sub", []Attr{{"alias", alias}}, children...)
+}
+
+func newVoice(voice PollyVoice, children ...Node) *Element {
+	return NewElement("voice", []Attr{{"name", string(voice)}}, children...)
+}
+
+func newW(role AmazonRole, children ...Node) *Element {
+	return NewElement("w", []Attr{{"role", string(role)}}, children...)
+}
+
+// Builder builds SSML speech as tree of elements, escaping text and validating the nesting when built.
+//
+// Methods named after a container element open it, End closes the element opened last:
+//
+//	s, err := ssml.NewBuilder().
+//		Text("Tom & Jerry").Break(ssml.BreakStrengthStrong, "").
+//		Voice(ssml.USVoiceJoanna).Text("Hello").End().
+//		Build()
+type Builder struct {
+	root  *Element
+	stack []*Element
+}
+
+// NewBuilder returns a builder of a speak element.
+func NewBuilder() *Builder {
+	root := NewElement("speak", nil)
+	return &Builder{root: root, stack: []*Element{root}}
+}
+
+func (b *Builder) add(n Node) *Builder {
+	top := b.stack[len(b.stack)-1]
+	top.Add(n)
+	return b
+}
+
+func (b *Builder) open(e *Element) *Builder {
+	b.add(e)
+	b.stack = append(b.stack, e)
+	return b
+}
+
+// End closes the element opened last.
+func (b *Builder) End() *Builder {
+	if len(b.stack) > 1 {
+		b.stack = b.stack[:len(b.stack)-1]
+	}
+	return b
+}
+
+// Text adds escaped text.
+func (b *Builder) Text(text string) *Builder {
+	return b.add(Text(text))
+}
+
+// Raw adds SSML as is, it is not validated.
+func (b *Builder) Raw(ssml string) *Builder {
+	return b.add(Raw(ssml))
+}
+
+// Node adds an element or text.
+func (b *Builder) Node(n Node) *Builder {
+	return b.add(n)
+}
+
+// Audio adds an MP3 file to play.
+func (b *Builder) Audio(src string) *Builder {
+	return b.add(newAudio(src))
+}
+
+// Break adds a break, with either strength or time.
+func (b *Builder) Break(strength BreakStrength, duration string) *Builder {
+	return b.add(newBreak(strength, duration))
+}
+
+// Phoneme adds text pronounced with the phonemes.
+func (b *Builder) Phoneme(alphabet PhonemeAlphabet, ph, text string) *Builder {
+	return b.add(newPhoneme(alphabet, ph, Text(text)))
+}
+
+// SayAs adds text spoken in a specific way.
+func (b *Builder) SayAs(interpretAs SayAsInterpretAs, format, text string) *Builder {
+	return b.add(newSayAs(interpretAs, format, Text(text)))
+}
+
+// Sub adds text spoken as the alias.
+func (b *Builder) Sub(alias, text string) *Builder {
+	return b.add(newSub(alias, Text(text)))
+}
+
+// W adds a word with a customized pronunciation.
+func (b *Builder) W(role AmazonRole, text string) *Builder {
+	return b.add(newW(role, Text(text)))
+}
+
+// Domain opens a domain of speech.
+func (b *Builder) Domain(domain AmazonDomain) *Builder {
+	return b.open(newDomain(domain))
+}
+
+// Effect opens a speech effect.
+func (b *Builder) Effect(effect AmazonEffect) *Builder {
+	return b.open(newEffect(effect))
+}
+
+// Emotion opens an emotion.
+func (b *Builder) Emotion(name AmazonEmotion, intensity AmazonEmotionIntensity) *Builder {
+	return b.open(newEmotion(name, intensity))
+}
+
+// Emphasis opens an emphasis.
+func (b *Builder) Emphasis(level EmphasisLevel) *Builder {
+	return b.open(newEmphasis(level))
+}
+
+// Lang opens a language.
+func (b *Builder) Lang(language string) *Builder {
+	return b.open(newLang(language))
+}
+
+// P opens a paragraph.
+func (b *Builder) P() *Builder {
+	return b.open(NewElement("p", nil))
+}
+
+// Prosody opens a change of volume, pitch and rate.
+func (b *Builder) Prosody(rate ProsodyRate, pitch ProsodyPitch, volume ProsodyVolume) *Builder {
+	return b.open(newProsody(rate, pitch, volume))
+}
+
+// S opens a sentence.
+func (b *Builder) S() *Builder {
+	return b.open(NewElement("s", nil))
+}
+
+// Voice opens an Amazon Polly voice.
+func (b *Builder) Voice(voice PollyVoice) *Builder {
+	return b.open(newVoice(voice))
+}
+
+// Root returns the speak element.
+func (b *Builder) Root() *Element {
+	return b.root
+}
+
+// Build validates and renders the speech, all opened elements must be closed.
+func (b *Builder) Build() (string, error) {
+	if len(b.stack) > 1 {
+		return "", fmt.Errorf("%w: <%s>", ErrUnclosed, b.stack[len(b.stack)-1].Name)
+	}
+	if err := Validate(b.root); err != nil {
+		return "", err
+	}
+	return b.root.String(), nil
+}
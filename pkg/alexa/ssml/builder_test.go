@@ -0,0 +1,90 @@
+package ssml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuilder_Build(t *testing.T) {
+	got, err := NewBuilder().
+		Text("Tom & Jerry <3").
+		Break(BreakStrengthStrong, "").
+		Voice(USVoiceJoanna).Lang("en-US").Text("Hello").End().End().
+		P().S().Sub("World Wide Web", "WWW").End().End().
+		Audio("https://example.com/a.mp3?x=1&y=2").
+		Build()
+
+	want := `<speak>Tom &amp; Jerry &lt;3<break strength="strong"/>` +
+		`<voice name="Joanna"><lang xml:lang="en-US">Hello</lang></voice>` +
+		`<p><s><sub alias="World Wide Web">WWW</sub></s></p>` +
+		`<audio src="https://example.com/a.mp3?x=1&amp;y=2"/></speak>`
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Build() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_BuildErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		wantErr error
+	}{
+		{"Unclosed", NewBuilder().Voice(USVoiceJoanna).Text("Hi"), ErrUnclosed},
+		{"EmotionInVoice", NewBuilder().Voice(USVoiceJoanna).Emotion(EmotionExcited, EmotionIntensityHigh).End().End(), ErrNesting},
+		{"VoiceInEmotion", NewBuilder().Emotion(EmotionExcited, EmotionIntensityHigh).Voice(USVoiceJoanna).End().End(), ErrNesting},
+		{"PInS", NewBuilder().S().P().End().End(), ErrNesting},
+		{"SInS", NewBuilder().S().S().End().End(), ErrNesting},
+		{"ElementInSayAs", NewBuilder().Node(NewElement("say-as", nil, newBreak("", "1s"))), ErrNesting},
+		{"AudioHTTP", NewBuilder().Audio("http://example.com/a.mp3"), ErrAttribute},
+		{"BreakTooLong", NewBuilder().Break("", "11s"), ErrAttribute},
+		{"BreakInvalid", NewBuilder().Break("", "1 minute"), ErrAttribute},
+		{"TooManyAudio", NewBuilder().
+			Audio("soundbank://a").Audio("soundbank://a").Audio("soundbank://a").
+			Audio("soundbank://a").Audio("soundbank://a").Audio("soundbank://a"), ErrTooManyAudio},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Build() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestElement_Attr(t *testing.T) {
+	e := newBreak("", "500ms")
+
+	if v, ok := e.Attr("time"); !ok || v != "500ms" {
+		t.Errorf("Attr() = %v, %v, want 500ms, true", v, ok)
+	}
+	if _, ok := e.Attr("strength"); ok {
+		t.Errorf("Attr() found strength")
+	}
+	if err := Validate(e); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestSpeak_Wrapper(t *testing.T) {
+	// the functions keep wrapping SSML as is
+	got := Speak(UseVoice(USVoiceJoanna, "<p>Hi</p>"))
+
+	if !strings.Contains(got, `<voice name="Joanna"><p>Hi</p></voice>`) {
+		t.Errorf("Speak() = %v", got)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	got := SayAs(SayAsInterpretAsCharacters, "", Escape("AT&T <3"))
+
+	if want := `<say-as interpret-as="characters">AT&amp;T &lt;3</say-as>`; got != want {
+		t.Errorf("SayAs() = %q, want %q", got, want)
+	}
+	if err := Check(Speak(got)); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package ssml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Parse errors.
+var (
+	ErrSyntax  = errors.New("invalid SSML")
+	ErrElement = errors.New("unsupported element")
+)
+
+// allowedAttrs are the elements supported by Alexa and their attributes.
+var allowedAttrs = map[string][]string{
+	"speak":          nil,
+	"amazon:domain":  {"name"},
+	"amazon:effect":  {"name"},
+	"amazon:emotion": {"name", "intensity"},
+	"audio":          {"src"},
+	"break":          {"strength", "time"},
+	"emphasis":       {"level"},
+	"lang":           {"xml:lang"},
+	"p":              nil,
+	"phoneme":        {"alphabet", "ph"},
+	"prosody":        {"rate", "pitch", "volume"},
+	"s":              nil,
+	"say-as":         {"interpret-as", "format"},
+	"sub":            {"alias"},
+	"voice":          {"name"},
+	"w":              {"role"},
+}
+
+// Parse parses SSML into a tree, it fails for elements and attributes not supported by Alexa.
+//
+// The SSML must be a single speak element. Text is unescaped, rendering the tree escapes it again.
+func Parse(s string) (*Element, error) {
+	dec := xml.NewDecoder(strings.NewReader(s))
+
+	var root *Element
+	var stack []*Element
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			e, err := newParsedElement(t)
+			if err != nil {
+				return nil, err
+			}
+			if len(stack) == 0 {
+				if root != nil || e.Name != "speak" {
+					return nil, fmt.Errorf("%w: root element must be a single <speak>", ErrSyntax)
+				}
+				root = e
+			} else {
+				stack[len(stack)-1].Add(e)
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			if len(stack) == 0 || stack[len(stack)-1].Name != rawName(t.Name) {
+				return nil, fmt.Errorf("%w: unexpected </%s>", ErrSyntax, rawName(t.Name))
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) == 0 {
+				if strings.TrimSpace(string(t)) != "" {
+					return nil, fmt.Errorf("%w: text outside of <speak>", ErrSyntax)
+				}
+				continue
+			}
+			stack[len(stack)-1].Add(Text(t))
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("%w: root element must be a single <speak>", ErrSyntax)
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("%w: <%s>", ErrUnclosed, stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+func newParsedElement(t xml.StartElement) (*Element, error) {
+	name := rawName(t.Name)
+	allowed, ok := allowedAttrs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: <%s>", ErrElement, name)
+	}
+
+	attrs := make([]Attr, 0, len(t.Attr))
+	for _, a := range t.Attr {
+		attr := rawName(a.Name)
+		if !contains(allowed, attr) {
+			return nil, fmt.Errorf("%w: '%s' of <%s>", ErrAttribute, attr, name)
+		}
+		attrs = append(attrs, Attr{Name: attr, Value: a.Value})
+	}
+	return NewElement(name, attrs), nil
+}
+
+// rawName returns the name with its prefix, e.g. "amazon:emotion".
+func rawName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Check parses and validates SSML, see Parse and Validate.
+func Check(s string) error {
+	e, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	return Validate(e)
+}
+
+// PlainText returns the readable text of SSML, e.g. for cards.
+func PlainText(s string) (string, error) {
+	e, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return e.PlainText(), nil
+}
+
+// PlainText returns the readable text of the element.
+//
+// Audio and breaks are dropped, sub is replaced by its alias and paragraphs are separated by new lines.
+// Raw nodes are written as is.
+func (e *Element) PlainText() string {
+	var b strings.Builder
+	e.plainText(&b)
+
+	var lines []string
+	for _, l := range strings.Split(b.String(), "\n") {
+		if l = strings.Join(strings.Fields(l), " "); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *Element) plainText(b *strings.Builder) {
+	switch e.Name {
+	case "audio":
+		return
+	case "break":
+		b.WriteString(" ")
+		return
+	case "sub":
+		alias, _ := e.Attr("alias")
+		b.WriteString(alias)
+		return
+	case "p":
+		b.WriteString("\n")
+		defer b.WriteString("\n")
+	case "s":
+		b.WriteString(" ")
+		defer b.WriteString(" ")
+	}
+
+	for _, c := range e.Children {
+		switch n := c.(type) {
+		case *Element:
+			n.plainText(b)
+		case Text:
+			b.WriteString(string(n))
+		case Raw:
+			b.WriteString(string(n))
+		}
+	}
+}
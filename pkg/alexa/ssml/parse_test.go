@@ -0,0 +1,83 @@
+package ssml
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	in := `<speak>Tom &amp; Jerry<break time="1s"/>` +
+		`<amazon:emotion name="excited" intensity="high">Yes</amazon:emotion>` +
+		`<voice name="Joanna"><lang xml:lang="en-US">Hello</lang></voice></speak>`
+
+	e, err := Parse(in)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := e.String(); got != in {
+		t.Errorf("Parse().String() = %v, want %v", got, in)
+	}
+	if got := e.Children[0]; got != Text("Tom & Jerry") {
+		t.Errorf("Parse() text = %v, want unescaped", got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ssml    string
+		wantErr error
+	}{
+		{"NoSpeak", "Hello", ErrSyntax},
+		{"WrongRoot", "<p>Hello</p>", ErrSyntax},
+		{"TwoRoots", "<speak>a</speak><speak>b</speak>", ErrSyntax},
+		{"TextOutside", "<speak>a</speak>b", ErrSyntax},
+		{"Mismatched", "<speak><p>a</s></speak>", ErrSyntax},
+		{"Unclosed", "<speak><p>a", ErrUnclosed},
+		{"UnknownElement", "<speak><blink>a</blink></speak>", ErrElement},
+		{"UnknownAttribute", `<speak><break length="1s"/></speak>`, ErrAttribute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.ssml); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check(`<speak><voice name="Joanna">Hi</voice></speak>`); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+	if err := Check(`<speak><voice name="Joanna"><amazon:domain name="news">Hi</amazon:domain></voice></speak>`); !errors.Is(err, ErrNesting) {
+		t.Errorf("Check() error = %v, want %v", err, ErrNesting)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		ssml string
+		want string
+	}{
+		{"Text", "<speak>Hello  world</speak>", "Hello world"},
+		{"Break", `<speak>Hello<break time="1s"/>world</speak>`, "Hello world"},
+		{"Audio", `<speak><audio src="soundbank://a"/>Hello</speak>`, "Hello"},
+		{"Sub", `<speak>Read the <sub alias="World Wide Web">WWW</sub></speak>`, "Read the World Wide Web"},
+		{"SayAs", `<speak>Room <say-as interpret-as="digits">12</say-as></speak>`, "Room 12"},
+		{"Paragraphs", "<speak><p>One.</p><p><s>Two.</s><s>Three.</s></p></speak>", "One.\nTwo. Three."},
+		{"Escaped", "<speak>Tom &amp; Jerry</speak>", "Tom & Jerry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PlainText(tt.ssml)
+			if err != nil {
+				t.Fatalf("PlainText() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("PlainText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
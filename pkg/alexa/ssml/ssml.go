@@ -1,12 +1,10 @@
 // Package ssml provides functions to simplify working with SSML speech.
+//
+// The functions wrap SSML text in an element, the Builder builds escaped and validated speech.
+// Parse reads SSML into the same tree, e.g. to check it or to derive the plain text for cards.
 // https://developer.amazon.com/en-US/docs/alexa/custom-skills/speech-synthesis-markup-language-ssml-reference.html#incompatible-tags
 package ssml
 
-import (
-	"fmt"
-	"strings"
-)
-
 // AmazonDomain is the domain of speech (news, music, ...).
 // https://developer.amazon.com/en-US/docs/alexa/custom-skills/speech-synthesis-markup-language-ssml-reference.html#amazon-domain
 type AmazonDomain string
@@ -21,7 +19,7 @@ const (
 
 // UseDomain uses a specific domain of speech.
 func UseDomain(domain AmazonDomain, text string) string {
-	return `<amazon:domain name="` + string(domain) + `">` + text + `</amazon:domain>`
+	return newDomain(domain, Raw(text)).String()
 }
 
 // AmazonEffect is a speech effect.
@@ -34,7 +32,7 @@ const (
 
 // UseEffect wraps text in an effect.
 func UseEffect(effect AmazonEffect, text string) string {
-	return `<amazon:effect name="` + string(effect) + `">` + text + `</amazon:effect>`
+	return newEffect(effect, Raw(text)).String()
 }
 
 // AmazonEmotion adds emotion to speech.
@@ -59,14 +57,14 @@ const (
 // UseEmotion wraps the text in an emotion tag.
 func UseEmotion(name AmazonEmotion, intensity AmazonEmotionIntensity, text string) string {
 	// <amazon:emotion name="excited" intensity="medium">
-	return `<amazon:emotion name="` + string(name) + `" intensity="` + string(intensity) + `">` + text + `</amazon:emotion>`
+	return newEmotion(name, intensity, Raw(text)).String()
 }
 
 // UseAudio uses an URL for an MP3 file to play.
 // https://developer.amazon.com/en-US/docs/alexa/custom-skills/speech-synthesis-markup-language-ssml-reference.html#audio
 // <audio src="soundbank://soundlibrary/transportation/amzn_sfx_car_accelerate_01" />.
 func UseAudio(src string) string {
-	return `<audio src="` + src + `"/>`
+	return newAudio(src).String()
 }
 
 // BreakStrength is one way to define the length of a break.
@@ -86,14 +84,7 @@ const (
 // only use `strength` or `time`, not both.
 // time in `ms` or `s` - may not exceed 10s.
 func Break(strength BreakStrength, time string) string {
-	params := []string{""}
-	if strength != "" {
-		params = append(params, `strength="`+string(strength)+`"`)
-	}
-	if time != "" {
-		params = append(params, `time="`+time+`"`)
-	}
-	return fmt.Sprintf(`<break%s/>`, strings.Join(params, " "))
+	return newBreak(strength, time).String()
 }
 
 // EmphasisLevel is the level of emphasis.
@@ -110,21 +101,17 @@ const (
 
 // UseEmphasis adds emphasis to the give text.
 func UseEmphasis(level EmphasisLevel, text string) string {
-	params := []string{""}
-	if level != "" {
-		params = append(params, `level="`+string(level)+`"`)
-	}
-	return fmt.Sprintf(`<emphasis%s>%s</emphasis>`, strings.Join(params, " "), text)
+	return newEmphasis(level, Raw(text)).String()
 }
 
 // UseLang speaks given text in the specified language.
 func UseLang(language, text string) string {
-	return `<lang xml:lang="` + language + `">` + text + `</lang>`
+	return newLang(language, Raw(text)).String()
 }
 
 // P wraps text in a paragraph.
 func P(text string) string {
-	return `<p>` + text + `</p>`
+	return NewElement("p", nil, Raw(text)).String()
 }
 
 // PhonemeAlphabet is the alphabet to interpret the `ph` parameter with.
@@ -141,7 +128,7 @@ const (
 // Phoneme pronounces the given text based on the provided alphabet and characters.
 // <phoneme alphabet="ipa" ph="pɪˈkɑːn">pecan</phoneme>.
 func Phoneme(alphabet PhonemeAlphabet, ph, text string) string {
-	return `<phoneme alphabet="` + string(alphabet) + `" ph="` + ph + `">` + text + `</phoneme>`
+	return newPhoneme(alphabet, ph, Raw(text)).String()
 }
 
 // ProsodyRate defines the speed of the voice. Can be provided in %: 100% is normal speed.
@@ -180,22 +167,12 @@ const (
 
 // Prosody modifies the volume, pitch, and rate of the tagged speech.
 func Prosody(rate ProsodyRate, pitch ProsodyPitch, volume ProsodyVolume, text string) string {
-	params := []string{""}
-	if rate != "" {
-		params = append(params, `rate="`+string(rate)+`"`)
-	}
-	if pitch != "" {
-		params = append(params, `pitch="`+string(pitch)+`"`)
-	}
-	if volume != "" {
-		params = append(params, `volume="`+string(volume)+`"`)
-	}
-	return fmt.Sprintf(`<prosody%s>%s</prosody>`, strings.Join(params, " "), text)
+	return newProsody(rate, pitch, volume, Raw(text)).String()
 }
 
 // S wraps text in a sentence.
 func S(text string) string {
-	return `<s>` + text + `</s>`
+	return NewElement("s", nil, Raw(text)).String()
 }
 
 // SayAsInterpretAs is the type of pronunciation.
@@ -236,22 +213,19 @@ const (
 // SayAs instructs the voice to "read" the text in a specific way.
 // <say-as interpret-as="cardinal">12345</say-as>.
 func SayAs(interpretAs SayAsInterpretAs, format, text string) string {
-	if interpretAs == SayAsInterpretAsDate && format != "" {
-		return `<say-as interpret-as="` + string(interpretAs) + `" format="` + format + `">` + text + `</say-as>`
-	}
-	return `<say-as interpret-as="` + string(interpretAs) + `">` + text + `</say-as>`
+	return newSayAs(interpretAs, format, Raw(text)).String()
 }
 
 // Speak wraps text in <speak> tags.
 func Speak(text string) string {
-	return "<speak>" + text + "</speak>"
+	return NewElement("speak", nil, Raw(text)).String()
 }
 
 // Sub provides an alias for the voice (e.g. how to pronounce abbreviations) for the text.
 // <sub alias="aluminum">Al</sub>
 // <sub alias="if I remember correctly">IIRC</sub>.
 func Sub(alias, text string) string {
-	return `<sub alias="` + alias + `">` + text + `</sub>`
+	return newSub(alias, Raw(text)).String()
 }
 
 // PollyVoice defines the voice name for speech.
@@ -314,12 +288,12 @@ const (
 
 // UseVoice wraps text in tags using a specific voice.
 func UseVoice(voice PollyVoice, text string) string {
-	return `<voice name="` + string(voice) + `">` + text + `</voice>`
+	return newVoice(voice, Raw(text)).String()
 }
 
 // UseVoiceLang wraps text in tags using a specific voice and language.
 func UseVoiceLang(voice PollyVoice, language, text string) string {
-	return `<voice name="` + string(voice) + `"><lang xml:lang="` + language + `">` + text + `</lang></voice>`
+	return newVoice(voice, newLang(language, Raw(text))).String()
 }
 
 // AmazonRole is a customized pronunciation of words.
@@ -345,5 +319,5 @@ const (
 // <w role="amazon:VB">read</w>
 // Similar to say-as, this tag customizes the pronunciation of words by specifying the word's part of speech.
 func W(role AmazonRole, text string) string {
-	return `<w role="` + string(role) + `">` + text + `</w>`
+	return newW(role, Raw(text)).String()
 }
@@ -2,7 +2,6 @@ package ssml
 
 import (
 	"fmt"
-	"github.com/drpsychick/go-alexa-lambda"
 	"testing"
 )
 
@@ -377,9 +376,9 @@ func TestUseVoiceLang(t *testing.T) {
 		want string
 	}{
 		{"VoiceLangNoArgs", args{}, `<voice name=""><lang xml:lang=""></lang></voice>`},
-		{"VoiceLang", args{DEVoiceMarlene, string(alexa.LocaleGerman), "ich heisse Marlene"},
+		{"VoiceLang", args{DEVoiceMarlene, "de-DE", "ich heisse Marlene"},
 			fmt.Sprintf(`<voice name="%s"><lang xml:lang="%s">%s</lang></voice>`,
-				string(DEVoiceMarlene), string(alexa.LocaleGerman), "ich heisse Marlene",
+				string(DEVoiceMarlene), "de-DE", "ich heisse Marlene",
 			),
 		},
 	}
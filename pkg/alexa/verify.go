@@ -0,0 +1,283 @@
+package alexa
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Verification constants, see
+// https://developer.amazon.com/en-US/docs/alexa/custom-skills/host-a-custom-skill-as-a-web-service.html
+const (
+	// HeaderSignatureCertChainURL is the header containing the URL of the signing certificate chain.
+	HeaderSignatureCertChainURL = "SignatureCertChainUrl"
+	// HeaderSignature256 is the header containing the base64 encoded SHA-256 signature of the body.
+	HeaderSignature256 = "Signature-256"
+	// CertSubjectAlternativeName is the domain the signing certificate must be issued for.
+	CertSubjectAlternativeName = "echo-api.amazon.com"
+	// TimestampTolerance is the maximum age of a request Alexa accepts.
+	TimestampTolerance = 150 * time.Second
+)
+
+// Verification errors.
+var (
+	ErrInvalidCertURL   = errors.New("invalid certificate chain url")
+	ErrInvalidCert      = errors.New("invalid certificate")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+)
+
+// CertFetcher fetches the PEM encoded certificate chain from the given URL.
+type CertFetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
+
+// CertFetcherFunc is an adapter allowing a function to be used as a CertFetcher.
+type CertFetcherFunc func(ctx context.Context, url string) ([]byte, error)
+
+// Fetch fetches the certificate chain.
+func (fn CertFetcherFunc) Fetch(ctx context.Context, url string) ([]byte, error) {
+	return fn(ctx, url)
+}
+
+// HTTPCertFetcher fetches certificate chains over HTTP.
+type HTTPCertFetcher struct {
+	Client *http.Client
+}
+
+// Fetch fetches the certificate chain.
+func (f *HTTPCertFetcher) Fetch(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %d", u, resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxRequestSize))
+}
+
+// Verifier verifies that requests are signed by Alexa.
+type Verifier struct {
+	// Fetcher fetches the certificate chains.
+	Fetcher CertFetcher
+	// Roots are the trusted root certificates, nil uses the system pool.
+	Roots *x509.CertPool
+	// Now returns the current time.
+	Now func() time.Time
+	// Tolerance is the maximum allowed difference between the request timestamp and now.
+	Tolerance time.Duration
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// NewVerifier returns a Verifier fetching certificates over HTTP, trusting the system roots.
+func NewVerifier() *Verifier {
+	return &Verifier{
+		Fetcher:   &HTTPCertFetcher{Client: &http.Client{Timeout: 5 * time.Second}},
+		Now:       time.Now,
+		Tolerance: TimestampTolerance,
+	}
+}
+
+// ValidateCertURL checks the certificate chain URL against the rules defined by Alexa.
+func ValidateCertURL(u string) error {
+	cu, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCertURL, err.Error())
+	}
+
+	if !strings.EqualFold(cu.Scheme, "https") {
+		return fmt.Errorf("%w: scheme must be https", ErrInvalidCertURL)
+	}
+	if !strings.EqualFold(cu.Hostname(), "s3.amazonaws.com") {
+		return fmt.Errorf("%w: host must be s3.amazonaws.com", ErrInvalidCertURL)
+	}
+	if cu.Port() != "" && cu.Port() != "443" {
+		return fmt.Errorf("%w: port must be 443", ErrInvalidCertURL)
+	}
+	if !strings.HasPrefix(path.Clean(cu.Path), "/echo.api/") {
+		return fmt.Errorf("%w: path must start with /echo.api/", ErrInvalidCertURL)
+	}
+
+	return nil
+}
+
+// Verify verifies the signature headers of the request against the body and checks the request timestamp.
+func (v *Verifier) Verify(r *http.Request, body []byte) error {
+	certURL := r.Header.Get(HeaderSignatureCertChainURL)
+	if err := ValidateCertURL(certURL); err != nil {
+		return err
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(r.Header.Get(HeaderSignature256))
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("%w: missing or malformed %s header", ErrInvalidSignature, HeaderSignature256)
+	}
+
+	cert, err := v.certificate(r.Context(), certURL)
+	if err != nil {
+		return err
+	}
+
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%w: unsupported public key", ErrInvalidCert)
+	}
+	hash := sha256.Sum256(body)
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], sig); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err.Error())
+	}
+
+	return v.verifyTimestamp(body)
+}
+
+func (v *Verifier) now() time.Time {
+	if v.Now == nil {
+		return time.Now()
+	}
+	return v.Now()
+}
+
+func (v *Verifier) verifyTimestamp(body []byte) error {
+	ts, err := time.Parse(time.RFC3339, jsoniter.Get(body, "request", "timestamp").ToString())
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidTimestamp, err.Error())
+	}
+
+	tolerance := v.Tolerance
+	if tolerance == 0 {
+		tolerance = TimestampTolerance
+	}
+	diff := v.now().Sub(ts)
+	if diff > tolerance || diff < -tolerance {
+		return fmt.Errorf("%w: request timestamp %s is outside the tolerance", ErrInvalidTimestamp, ts)
+	}
+
+	return nil
+}
+
+// certificate returns the validated signing certificate from cache or fetches it.
+func (v *Verifier) certificate(ctx context.Context, u string) (*x509.Certificate, error) {
+	v.mu.Lock()
+	cert, ok := v.certs[u]
+	v.mu.Unlock()
+	if ok && v.now().Before(cert.NotAfter) {
+		return cert, nil
+	}
+
+	if v.Fetcher == nil {
+		return nil, fmt.Errorf("%w: no certificate fetcher", ErrInvalidCert)
+	}
+	data, err := v.Fetcher.Fetch(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCert, err.Error())
+	}
+
+	cert, err = v.verifyChain(data)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	if v.certs == nil {
+		v.certs = map[string]*x509.Certificate{}
+	}
+	v.certs[u] = cert
+	v.mu.Unlock()
+
+	return cert, nil
+}
+
+// verifyChain parses the PEM chain and validates the leaf certificate.
+func (v *Verifier) verifyChain(data []byte) (*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCert, err.Error())
+		}
+		certs = append(certs, c)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%w: no certificate found", ErrInvalidCert)
+	}
+
+	leaf := certs[0]
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+
+	opts := x509.VerifyOptions{
+		DNSName:       CertSubjectAlternativeName,
+		Roots:         v.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   v.now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	if _, err := leaf.Verify(opts); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCert, err.Error())
+	}
+
+	return leaf, nil
+}
+
+// WithVerification verifies every request before passing it on to the handler.
+//
+// Requests failing verification are rejected with status 400.
+func WithVerification(h http.Handler, v *Verifier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+		if err != nil {
+			http.Error(w, "could not read request", http.StatusBadRequest)
+			return
+		}
+
+		if err := v.Verify(r, body); err != nil {
+			http.Error(w, "request verification failed", http.StatusBadRequest)
+			return
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		h.ServeHTTP(w, r)
+	})
+}
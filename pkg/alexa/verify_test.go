@@ -0,0 +1,215 @@
+package alexa
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCertURL = "https://s3.amazonaws.com/echo.api/echo-api-cert.pem"
+
+// fakeCA is a local certificate authority issuing Alexa-like signing certificates.
+type fakeCA struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newFakeCA(t *testing.T) *fakeCA {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fake root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return &fakeCA{key: key, cert: cert}
+}
+
+func (ca *fakeCA) pool() *x509.CertPool {
+	p := x509.NewCertPool()
+	p.AddCert(ca.cert)
+	return p
+}
+
+// issue returns a PEM encoded leaf certificate for the dns name and its private key.
+func (ca *fakeCA) issue(t *testing.T, dnsName string) ([]byte, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), key
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, body []byte) string {
+	t.Helper()
+
+	hash := sha256.Sum256(body)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func newSignedRequest(body []byte, certURL, sig string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	r.Header.Set(HeaderSignatureCertChainURL, certURL)
+	r.Header.Set(HeaderSignature256, sig)
+	return r
+}
+
+func newTestVerifier(ca *fakeCA, chain []byte) *Verifier {
+	return &Verifier{
+		Fetcher: CertFetcherFunc(func(ctx context.Context, url string) ([]byte, error) {
+			return chain, nil
+		}),
+		Roots: ca.pool(),
+		Now:   time.Now,
+	}
+}
+
+func TestValidateCertURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://s3.amazonaws.com/echo.api/echo-api-cert.pem", true},
+		{"https://s3.amazonaws.com:443/echo.api/echo-api-cert.pem", true},
+		{"HTTPS://s3.amazonaws.com/echo.api/echo-api-cert.pem", true},
+		{"https://S3.AMAZONAWS.COM/echo.api/echo-api-cert.pem", true},
+		{"https://s3.amazonaws.com/echo.api/../echo.api/echo-api-cert.pem", true},
+		{"http://s3.amazonaws.com/echo.api/echo-api-cert.pem", false},
+		{"https://notamazon.com/echo.api/echo-api-cert.pem", false},
+		{"https://s3.amazonaws.com/EcHo.aPi/echo-api-cert.pem", false},
+		{"https://s3.amazonaws.com/invalid.path/echo-api-cert.pem", false},
+		{"https://s3.amazonaws.com:563/echo.api/echo-api-cert.pem", false},
+		{"https://s3.amazonaws.com/echo.api/../invalid/echo-api-cert.pem", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateCertURL(tt.url)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidCertURL))
+			}
+		})
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	ca := newFakeCA(t)
+	chain, key := ca.issue(t, CertSubjectAlternativeName)
+	v := newTestVerifier(ca, chain)
+
+	body := []byte(`{"request":{"timestamp":"` + time.Now().UTC().Format(time.RFC3339) + `"}}`)
+	err := v.Verify(newSignedRequest(body, testCertURL, sign(t, key, body)), body)
+	assert.NoError(t, err)
+
+	// tampered body
+	tampered := []byte(`{"request":{"timestamp":"` + time.Now().UTC().Format(time.RFC3339) + `","x":1}}`)
+	err = v.Verify(newSignedRequest(tampered, testCertURL, sign(t, key, body)), tampered)
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+
+	// missing signature
+	err = v.Verify(newSignedRequest(body, testCertURL, ""), body)
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+
+	// invalid url
+	err = v.Verify(newSignedRequest(body, "https://example.com/echo.api/cert.pem", sign(t, key, body)), body)
+	assert.True(t, errors.Is(err, ErrInvalidCertURL))
+
+	// old timestamp
+	old := []byte(`{"request":{"timestamp":"` + time.Now().Add(-time.Hour).UTC().Format(time.RFC3339) + `"}}`)
+	err = v.Verify(newSignedRequest(old, testCertURL, sign(t, key, old)), old)
+	assert.True(t, errors.Is(err, ErrInvalidTimestamp))
+}
+
+func TestVerifier_VerifyInvalidCert(t *testing.T) {
+	ca := newFakeCA(t)
+	body := []byte(`{"request":{"timestamp":"` + time.Now().UTC().Format(time.RFC3339) + `"}}`)
+
+	// wrong subject alternative name
+	chain, key := ca.issue(t, "example.com")
+	v := newTestVerifier(ca, chain)
+	err := v.Verify(newSignedRequest(body, testCertURL, sign(t, key, body)), body)
+	assert.True(t, errors.Is(err, ErrInvalidCert))
+
+	// untrusted CA
+	other := newFakeCA(t)
+	chain, key = other.issue(t, CertSubjectAlternativeName)
+	v = newTestVerifier(ca, chain)
+	err = v.Verify(newSignedRequest(body, testCertURL, sign(t, key, body)), body)
+	assert.True(t, errors.Is(err, ErrInvalidCert))
+
+	// expired
+	chain, key = ca.issue(t, CertSubjectAlternativeName)
+	v = newTestVerifier(ca, chain)
+	v.Now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	future := []byte(`{"request":{"timestamp":"` + v.Now().UTC().Format(time.RFC3339) + `"}}`)
+	err = v.Verify(newSignedRequest(future, testCertURL, sign(t, key, future)), future)
+	assert.True(t, errors.Is(err, ErrInvalidCert))
+
+	// no certificate
+	v = newTestVerifier(ca, []byte("no pem"))
+	err = v.Verify(newSignedRequest(body, testCertURL, sign(t, key, body)), body)
+	assert.True(t, errors.Is(err, ErrInvalidCert))
+}
+
+func TestWithVerification(t *testing.T) {
+	ca := newFakeCA(t)
+	chain, key := ca.issue(t, CertSubjectAlternativeName)
+	v := newTestVerifier(ca, chain)
+
+	h := WithVerification(&Server{
+		Handler: HandlerFunc(func(b *ResponseBuilder, r *RequestEnvelope) {
+			b.WithSimpleCard("title", "text")
+		}),
+	}, v)
+
+	body := []byte(`{"request":{"timestamp":"` + time.Now().UTC().Format(time.RFC3339) + `"}}`)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newSignedRequest(body, testCertURL, sign(t, key, body)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"title":"title"`)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newSignedRequest(body, testCertURL, "invalid"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
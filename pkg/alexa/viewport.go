@@ -0,0 +1,114 @@
+package alexa
+
+// DeviceClass is the class of a device by its viewport.
+type DeviceClass string
+
+// Device classes.
+const (
+	DeviceHeadless     DeviceClass = "headless"
+	DeviceHubRound     DeviceClass = "hub-round"
+	DeviceHubLandscape DeviceClass = "hub-landscape"
+	DeviceHubPortrait  DeviceClass = "hub-portrait"
+	DeviceTV           DeviceClass = "tv"
+	DeviceMobile       DeviceClass = "mobile"
+)
+
+// Viewport returns the viewport of the device, nil for devices without a screen.
+func (r *RequestEnvelope) Viewport() *ContextViewport {
+	if r.Context == nil {
+		return nil
+	}
+	return r.Context.Viewport
+}
+
+// DeviceClass classifies the device of the request by its viewport.
+//
+// Hubs, PCs and automotive screens are classified by their shape and orientation.
+func (r *RequestEnvelope) DeviceClass() DeviceClass {
+	vp := r.Viewport()
+	if vp == nil {
+		return DeviceHeadless
+	}
+
+	switch vp.Mode {
+	case ContextViewportModeTV:
+		return DeviceTV
+	case ContextViewportModeMobile:
+		return DeviceMobile
+	}
+	if vp.Shape == ContextViewportShapeRound {
+		return DeviceHubRound
+	}
+	if vp.PixelHeight > vp.PixelWidth {
+		return DeviceHubPortrait
+	}
+	return DeviceHubLandscape
+}
+
+// IsHeadless returns true if the device has no screen.
+func (r *RequestEnvelope) IsHeadless() bool {
+	return r.DeviceClass() == DeviceHeadless
+}
+
+// OutputMode is how a response is presented.
+type OutputMode string
+
+// Output modes.
+const (
+	// OutputVoice presents the speech only.
+	OutputVoice OutputMode = "voice"
+	// OutputCard adds a card to the speech, shown in the Alexa app.
+	OutputCard OutputMode = "card"
+	// OutputAPL renders the title, text and image on the screen of the device.
+	OutputAPL OutputMode = "apl"
+)
+
+// ImageSize is the size of an image.
+type ImageSize string
+
+// Image sizes.
+const (
+	ImageSmall ImageSize = "small"
+	ImageLarge ImageSize = "large"
+)
+
+// URL returns the URL of the image in the size, the other size if it has none.
+func (i *Image) URL(size ImageSize) string {
+	if (size == ImageSmall && i.SmallImageURL != "") || i.LargeImageURL == "" {
+		return i.SmallImageURL
+	}
+	return i.LargeImageURL
+}
+
+// ResponsePolicy returns the output mode and image size for a device class.
+type ResponsePolicy func(class DeviceClass) (OutputMode, ImageSize)
+
+// DefaultResponsePolicy renders APL on hubs and TVs and adds a card on headless and mobile devices,
+// round hubs get small images.
+func DefaultResponsePolicy(class DeviceClass) (OutputMode, ImageSize) {
+	switch class {
+	case DeviceHubRound:
+		return OutputAPL, ImageSmall
+	case DeviceHubLandscape, DeviceHubPortrait, DeviceTV:
+		return OutputAPL, ImageLarge
+	default:
+		return OutputCard, ImageLarge
+	}
+}
+
+// responseDocument returns an APL document showing the title, text and image of the response.
+func responseDocument(resp Response, text string, size ImageSize) *APLBuilder {
+	props := map[string]interface{}{"title": resp.Title, "text": text}
+	items := []APLComponent{
+		APLText("${payload.properties.title}"),
+		APLText("${payload.properties.text}"),
+	}
+	if resp.Image != nil {
+		props["image"] = resp.Image.URL(size)
+		items = append(items, APLImage("${payload.properties.image}"))
+	}
+
+	return NewAPLBuilder().
+		WithItems(APLContainer(items...)).
+		WithObjectDatasource("payload", props)
+}
@@ -0,0 +1,96 @@
+package alexa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestEnvelope_DeviceClass(t *testing.T) {
+	tests := []struct {
+		name     string
+		viewport *ContextViewport
+		want     DeviceClass
+	}{
+		{"Headless", nil, DeviceHeadless},
+		{"HubRound", &ContextViewport{Mode: ContextViewportModeHUB, Shape: ContextViewportShapeRound}, DeviceHubRound},
+		{"HubLandscape", &ContextViewport{
+			Mode: ContextViewportModeHUB, Shape: ContextViewportShapeRectangle, PixelWidth: 1280, PixelHeight: 800,
+		}, DeviceHubLandscape},
+		{"HubPortrait", &ContextViewport{
+			Mode: ContextViewportModeHUB, Shape: ContextViewportShapeRectangle, PixelWidth: 800, PixelHeight: 1280,
+		}, DeviceHubPortrait},
+		{"TV", &ContextViewport{Mode: ContextViewportModeTV, Shape: ContextViewportShapeRectangle}, DeviceTV},
+		{"Mobile", &ContextViewport{Mode: ContextViewportModeMobile, Shape: ContextViewportShapeRectangle}, DeviceMobile},
+		{"PC", &ContextViewport{Mode: ContextViewportModePC, Shape: ContextViewportShapeRectangle}, DeviceHubLandscape},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RequestEnvelope{Context: &Context{Viewport: tt.viewport}}
+
+			assert.Equal(t, tt.want, r.DeviceClass())
+			assert.Equal(t, tt.viewport == nil, r.IsHeadless())
+		})
+	}
+	assert.Equal(t, DeviceHeadless, (&RequestEnvelope{}).DeviceClass())
+}
+
+func TestImage_URL(t *testing.T) {
+	img := &Image{SmallImageURL: "small", LargeImageURL: "large"}
+
+	assert.Equal(t, "small", img.URL(ImageSmall))
+	assert.Equal(t, "large", img.URL(ImageLarge))
+	assert.Equal(t, "large", (&Image{LargeImageURL: "large"}).URL(ImageSmall))
+	assert.Equal(t, "small", (&Image{SmallImageURL: "small"}).URL(ImageLarge))
+}
+
+func TestResponseBuilder_WithPolicy(t *testing.T) {
+	resp := Response{
+		Title:  "title",
+		Text:   "text",
+		Speech: "<speak>hello</speak>",
+		Image:  &Image{SmallImageURL: "small", LargeImageURL: "large"},
+	}
+	apl := map[string]struct{}{InterfaceAPL: {}}
+	round := &ContextViewport{Mode: ContextViewportModeHUB, Shape: ContextViewportShapeRound}
+
+	// headless devices get a standard card
+	b := &ResponseBuilder{}
+	b.WithRequest(&RequestEnvelope{}).With(resp)
+	res := b.Build()
+	assert.Equal(t, "Standard", res.Response.Card.Type)
+	assert.Equal(t, "text", res.Response.Card.Text)
+	assert.Equal(t, resp.Image, res.Response.Card.Image)
+	assert.Empty(t, res.Response.Directives)
+
+	// hubs get an APL document with the image in their size
+	r := &RequestEnvelope{Context: &Context{System: &ContextSystem{}, Viewport: round}}
+	r.Context.System.Device.SupportedInterfaces = apl
+	b = &ResponseBuilder{}
+	b.WithRequest(r).With(resp)
+	res = b.Build()
+	assert.Nil(t, res.Response.Card)
+	assert.Len(t, res.Response.Directives, 1)
+	assert.Equal(t, DirectiveTypeAPLRenderDocument, res.Response.Directives[0].Type)
+	assert.Equal(t, map[string]interface{}{
+		"type":       APLDatasourceTypeObject,
+		"properties": map[string]interface{}{"title": "title", "text": "text", "image": "small"},
+	}, res.Response.Directives[0].Datasources["payload"])
+	assert.Equal(t, "<speak>hello</speak>", res.Response.OutputSpeech.SSML)
+
+	// hubs without APL fall back to a card
+	r.Context.System.Device.SupportedInterfaces = nil
+	b = &ResponseBuilder{}
+	b.WithRequest(r).With(resp)
+	assert.Equal(t, "Standard", b.Build().Response.Card.Type)
+
+	// voice only
+	b = &ResponseBuilder{}
+	b.WithResponsePolicy(func(DeviceClass) (OutputMode, ImageSize) {
+		return OutputVoice, ImageLarge
+	}).With(resp)
+	res = b.Build()
+	assert.Nil(t, res.Response.Card)
+	assert.Empty(t, res.Response.Directives)
+	assert.Equal(t, "<speak>hello</speak>", res.Response.OutputSpeech.SSML)
+}
@@ -2,19 +2,58 @@
 package server
 
 import (
-	"github.com/drpsychick/go-alexa-lambda"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
 )
 
-// Application defines the interface to the app.
-type Application interface {
-	Handle()
-	Help() (string, string)
+// Server timeouts, Alexa expects a response within 8 seconds.
+const (
+	ReadTimeout  = 5 * time.Second
+	WriteTimeout = 10 * time.Second
+
+	// ShutdownTimeout is the time in-flight requests get to finish on shutdown.
+	ShutdownTimeout = 15 * time.Second
+)
+
+// Config contains the options for the server.
+type Config struct {
+	Verifier *alexa.Verifier
 }
 
-// NewServer creates a new Mux instance.
-func NewServer(app Application) alexa.Handler {
-	return nil
+// OptFunc defines the functions to be passed to NewServer.
+type OptFunc func(cfg *Config)
+
+// WithVerifier verifies the Alexa signature of every request with the given verifier.
+func WithVerifier(v *alexa.Verifier) OptFunc {
+	return func(cfg *Config) {
+		cfg.Verifier = v
+	}
 }
 
-// Handler defines the handler function.
-type Handler func(alexa.RequestEnvelope) (alexa.ResponseEnvelope, error)
+// NewServer returns a http server serving the handler on the given address.
+func NewServer(addr string, h alexa.Handler, opts ...OptFunc) (*http.Server, error) {
+	if h == nil {
+		return nil, errors.New("server: cannot serve empty handler")
+	}
+
+	// run all OptFuncs
+	cfg := &Config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var handler http.Handler = &alexa.Server{Handler: h}
+	if cfg.Verifier != nil {
+		handler = alexa.WithVerification(handler, cfg.Verifier)
+	}
+
+	return &http.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  ReadTimeout,
+		WriteTimeout: WriteTimeout,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package server_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServer(t *testing.T) {
+	_, err := server.NewServer(":8080", nil)
+	assert.Error(t, err)
+
+	srv, err := server.NewServer(":8080", alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+		b.WithSimpleCard("title", "text")
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, ":8080", srv.Addr)
+
+	ts := httptest.NewServer(srv.Handler)
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"version":"1.0"}`))
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, string(body), `"title":"title"`)
+}
+
+func TestNewServer_WithVerifier(t *testing.T) {
+	srv, err := server.NewServer(":8080", alexa.HandlerFunc(func(b *alexa.ResponseBuilder, r *alexa.RequestEnvelope) {
+		b.WithSimpleCard("title", "text")
+	}), server.WithVerifier(alexa.NewVerifier()))
+	assert.NoError(t, err)
+
+	ts := httptest.NewServer(srv.Handler)
+	defer ts.Close()
+
+	// unsigned requests are rejected
+	res, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"version":"1.0"}`))
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
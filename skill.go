@@ -1,55 +1,35 @@
 package alfalfa
 
 import (
+	"errors"
+
 	"github.com/drpsychick/alexa-go-cloudformation-demo/loca"
-	"github.com/drpsychick/go-alexa-lambda/skill"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa"
+	"github.com/drpsychick/alexa-go-cloudformation-demo/pkg/alexa/skill"
 )
 
-// NewSkill returns a configured SkillBuilder.
+// NewSkill returns a configured SkillBuilder with an empty model.
 func NewSkill() *skill.SkillBuilder {
-	return skill.NewSkillBuilder().
+	sb := skill.NewSkillBuilder().
 		WithLocaleRegistry(loca.Registry).
 		WithCategory(skill.CategoryOrganizersAndAssistants).
-		WithPrivacyFlag(skill.FlagIsExportCompliant, true)
-}
-
-// CreateSkillModels generates and returns a list of Models.
-func CreateSkillModels(s *skill.SkillBuilder) (map[string]*skill.Model, error) {
-	m := s.Model().
-		WithDelegationStrategy(skill.DelegationSkillResponse)
-
-	// we define intents, slots, types in lambda,
-	// that's why `newLambda` must be called before this, if not it will panic.
-
-	// this "breaks" `ask dialog --replay` when starting the intent without any slots
-	// (as Alexa will try to get the Region slot from the user)
-	// Prompts are part of the Alexa dialog, so independent of lambda.
-	m.WithElicitationSlotPrompt(loca.AWSStatus, loca.TypeRegionName)
-	// add variations (texts) to the prompt
-	m.ElicitationPrompt(loca.AWSStatus, loca.TypeRegionName).
-		WithVariation("PlainText").
-		WithVariation("SSML")
+		WithPrivacyFlag(skill.FlagIsExportCompliant, true).
+		WithModel()
 
-	// do not require elicitation for Region
-	m.Intent(loca.AWSStatus).Slot(loca.TypeRegionName).WithElicitation(false)
-
-	// this "breaks" the `ask dialog --replay` testing as alexa asks the user to validate the input
-	// m.WithConfirmationSlotPrompt(loca.AWSStatus, loca.TypeAreaName)
-	// m.ConfirmationPrompt(loca.AWSStatus, loca.TypeAreaName).
-	//	WithVariation("SSML")
-
-	// create a Validation prompt, connected to type-values
-	m.WithValidationSlotPrompt(loca.TypeRegionName, skill.ValidationTypeHasMatch)
-	m.ValidationPrompt(loca.TypeRegionName, skill.ValidationTypeHasMatch).
-		WithVariation("PlainText")
-
-	// ValidationTypeInSet requires values -> we need to pass a key
-	m.WithValidationSlotPrompt(loca.TypeRegionName, skill.ValidationTypeInSet, loca.TypeRegionValues)
-	m.ValidationPrompt(loca.TypeRegionName, skill.ValidationTypeInSet).
-		WithVariation("PlainText")
-
-	// m.Intent(loca.AWSStatus).Slot(loca.TypeRegionName).
-	//	WithValidationRule(alexa.ValidationTypeHasMatch)
+	sb.Model().WithDelegationStrategy(skill.DelegationSkillResponse)
+	return sb
+}
 
+// CreateSkillModels registers the intents with the model and generates and returns a list of Models.
+//
+// The intents are defined in lambda (see lambda.Intents), the mux only consumes the same definitions.
+func CreateSkillModels(s *skill.SkillBuilder, intents []alexa.IntentDefinition) (map[string]*skill.Model, error) {
+	if len(intents) == 0 {
+		return nil, errors.New("skill: no intents to create models for")
+	}
+
+	for _, d := range intents {
+		d.Register(nil, s)
+	}
 	return s.BuildModels()
 }